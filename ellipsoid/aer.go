@@ -0,0 +1,64 @@
+package ellipsoid
+
+import "math"
+
+/*
+LookAngles returns the azimuth, the elevation and the slant range from an
+observer at lat0, lon0, h0 to a target at lat, lon, h. The azimuth is
+measured clockwise from north and honors BearingSymmetry, the elevation
+is measured up from the local horizontal plane. The heights and the range
+are in the distance units.
+
+	az, el, slant := geo.LookAngles(lat0, lon0, h0, lat, lon, h)
+*/
+func (ellipsoid Ellipsoid) LookAngles(lat0, lon0, h0, lat, lon, h float64) (azimuth, elevation, slantRange float64) {
+	x, y, z := ellipsoid.ToECEFUnits(lat, lon, h)
+	return ellipsoid.LookAnglesECEF(lat0, lon0, h0, x, y, z)
+}
+
+/*
+LookAnglesECEF is LookAngles for a target given in ECEF in meters.
+
+	az, el, slant := geo.LookAnglesECEF(lat0, lon0, h0, x, y, z)
+*/
+func (ellipsoid Ellipsoid) LookAnglesECEF(lat0, lon0, h0, x, y, z float64) (azimuth, elevation, slantRange float64) {
+	e, n, u := ellipsoid.NewLocalCartesian(lat0, lon0, h0).fromECEF(x, y, z)
+	horizontal := math.Hypot(e, n)
+	azimuth = ellipsoid.adjustBearing(math.Atan2(e, n), pi)
+	elevation = math.Atan2(u, horizontal)
+	slantRange = math.Hypot(horizontal, u)
+	if ellipsoid.Units == Degrees {
+		azimuth = rad2deg(azimuth)
+		elevation = rad2deg(elevation)
+	}
+	return azimuth, elevation, slantRange
+}
+
+/*
+FromLookAngles is the inverse of LookAngles. It returns the target seen
+from the observer at lat0, lon0, h0 at the azimuth, the elevation and the
+slant range.
+
+	lat, lon, h := geo.FromLookAngles(lat0, lon0, h0, az, el, slant)
+*/
+func (ellipsoid Ellipsoid) FromLookAngles(lat0, lon0, h0, azimuth, elevation, slantRange float64) (lat, lon, h float64) {
+	return ellipsoid.ToLLA(ellipsoid.FromLookAnglesECEF(lat0, lon0, h0, azimuth, elevation, slantRange))
+}
+
+/*
+FromLookAnglesECEF is FromLookAngles returning the target in ECEF in
+meters.
+
+	x, y, z := geo.FromLookAnglesECEF(lat0, lon0, h0, az, el, slant)
+*/
+func (ellipsoid Ellipsoid) FromLookAnglesECEF(lat0, lon0, h0, azimuth, elevation, slantRange float64) (x, y, z float64) {
+	if ellipsoid.Units == Degrees {
+		azimuth = deg2rad(azimuth)
+		elevation = deg2rad(elevation)
+	}
+	horizontal := slantRange * math.Cos(elevation)
+	e := horizontal * math.Sin(azimuth)
+	n := horizontal * math.Cos(azimuth)
+	u := slantRange * math.Sin(elevation)
+	return ellipsoid.NewLocalCartesian(lat0, lon0, h0).toECEF(e, n, u)
+}
@@ -0,0 +1,61 @@
+package ellipsoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookAngles(t *testing.T) {
+	e1 := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	// Straight up is at 90 degrees elevation.
+	_, el, slant := e1.LookAngles(45, 7, 100, 45, 7, 1100)
+	deltaWithin(t, loc(), el, 90, 1e-9)
+	deltaWithin(t, loc(), slant, 1000, 1e-6)
+
+	// A nearby point to the east is nearly on the horizon, below it by
+	// the curvature of the earth.
+	az, el, slant := e1.LookAngles(0, 0, 0, 0, 0.01, 0)
+	deltaWithin(t, loc(), az, 90, 1e-9)
+	deltaWithin(t, loc(), el, -0.005, 1e-6)
+	deltaWithin(t, loc(), slant, 1113.19, 1e-2)
+
+	// A geostationary satellite at the longitude of an observer on the
+	// equator is overhead; seen from 45N it is due south.
+	sx, sy, sz := 42164e3*math.Cos(10*degree), 42164e3*math.Sin(10*degree), 0.0
+	_, el, slant = e1.LookAnglesECEF(0, 10, 0, sx, sy, sz)
+	deltaWithin(t, loc(), el, 90, 1e-9)
+	deltaWithin(t, loc(), slant, 42164e3-e1.Ellipse.Equatorial, 1e-6)
+	az, el, _ = e1.LookAnglesECEF(45, 10, 0, sx, sy, sz)
+	deltaWithin(t, loc(), az, 180, 1e-9)
+	if el <= 0 || el >= 45 {
+		t.Errorf("LookAnglesECEF: elevation %v of the satellite is not in (0, 45)", el)
+	}
+
+	// With symmetric bearings west is negative.
+	s := Init("WGS84", Radians, Meter, LongitudeIsSymmetric, BearingIsSymmetric)
+	az, _, _ = s.LookAngles(0, 0, 0, 0, -0.001, 0)
+	deltaWithin(t, loc(), az, -pi/2, 1e-9)
+}
+
+func TestFromLookAngles(t *testing.T) {
+	e1 := Init("WGS84", Radians, Meter, LongitudeNotSymmetric, BearingIsSymmetric)
+
+	lat0, lon0, h0 := 52.5*degree, 13.4*degree, 34.0
+	for _, p := range [][3]float64{
+		{52.6 * degree, 13.2 * degree, 10000},
+		{48.1 * degree, 11.6 * degree, 500},
+	} {
+		az, el, slant := e1.LookAngles(lat0, lon0, h0, p[0], p[1], p[2])
+		lat, lon, h := e1.FromLookAngles(lat0, lon0, h0, az, el, slant)
+		deltaWithin(t, loc(), lat, p[0], 1e-9)
+		deltaWithin(t, loc(), lon, p[1], 1e-9)
+		deltaWithin(t, loc(), h, p[2], 1e-2)
+
+		x, y, z := e1.FromLookAnglesECEF(lat0, lon0, h0, az, el, slant)
+		wx, wy, wz := e1.ToECEF(p[0], p[1], p[2])
+		deltaWithin(t, loc(), x, wx, 1e-6)
+		deltaWithin(t, loc(), y, wy, 1e-6)
+		deltaWithin(t, loc(), z, wz, 1e-6)
+	}
+}
@@ -0,0 +1,244 @@
+package ellipsoid
+
+// Auxiliary latitudes. Each maps the ellipsoid onto a sphere so that
+// some property is preserved: the direction from the centre
+// (geocentric), the parallels of the circumscribed sphere (parametric),
+// angles (conformal), areas (authalic) or meridian distances
+// (rectifying). The isometric latitude is the ordinate of the Mercator
+// projection.
+
+import "math"
+
+// eatanhe returns e * atanh(e * x) for the signed eccentricity es.
+func eatanhe(x, es float64) float64 {
+	if es > 0 {
+		return es * math.Atanh(es*x)
+	}
+	return -es * math.Atan(es*x)
+}
+
+// taupf returns tan(chi) for tau = tan(phi), where chi is the conformal
+// latitude.
+func taupf(tau, es float64) float64 {
+	tau1 := math.Hypot(1, tau)
+	sig := math.Sinh(eatanhe(tau/tau1, es))
+	return math.Hypot(1, sig)*tau - sig*tau1
+}
+
+// tauf is the inverse of taupf. It uses Newton's method, see
+// C. F. F. Karney, Transverse Mercator with an accuracy of a few
+// nanometers, J. Geodesy 85, 475-485 (2011).
+func tauf(taup, es float64) float64 {
+	const numit = 5
+	tol := math.Sqrt(dblEpsilon) / 10
+	taumax := 2 / math.Sqrt(dblEpsilon)
+	e2m := 1 - sq(es)
+	var tau float64
+	if math.Abs(taup) > 70 {
+		tau = taup * math.Exp(eatanhe(1, es))
+	} else {
+		tau = taup / e2m
+	}
+	stol := tol * math.Max(1, math.Abs(taup))
+	if !(math.Abs(tau) < taumax) {
+		return tau // handles +/-inf and nan
+	}
+	for i := 0; i < numit; i++ {
+		taupa := taupf(tau, es)
+		dtau := (taup - taupa) * (1 + e2m*sq(tau)) /
+			(e2m * math.Hypot(1, tau) * math.Hypot(1, taupa))
+		tau += dtau
+		if !(math.Abs(dtau) >= stol) {
+			break
+		}
+	}
+	return tau
+}
+
+// eccentricity returns the signed eccentricity, negative for prolate
+// ellipsoids.
+func (e ellipse) eccentricity() float64 {
+	e2 := e.eccentricitySquared()
+	return math.Copysign(math.Sqrt(math.Abs(e2)), e2)
+}
+
+// authalicQ returns q(phi) / (1 - e^2), where the area between the
+// equator and the latitude phi in radians is proportional to q.
+func (e ellipse) authalicQ(phi float64) float64 {
+	e2 := e.eccentricitySquared()
+	s := math.Sin(phi)
+	var atanhee float64 // atanh(e * s) / e
+	if es := e.eccentricity(); es != 0 {
+		atanhee = eatanhe(s, es) / e2
+	} else {
+		atanhee = s
+	}
+	return s/(1-e2*sq(s)) + atanhee
+}
+
+// authalic returns the authalic latitude of phi in radians.
+func (e ellipse) authalic(phi float64) float64 {
+	return math.Asin(math.Max(-1, math.Min(1, e.authalicQ(phi)/e.authalicQ(pi/2))))
+}
+
+// fromAuthalic is the inverse of authalic. It starts from the series
+// in e^2 and refines it with Newton's method.
+func (e ellipse) fromAuthalic(xi float64) float64 {
+	if math.Abs(xi) >= pi/2 {
+		return xi
+	}
+	e2 := e.eccentricitySquared()
+	e4, e6 := e2*e2, e2*e2*e2
+	phi := xi + (e2/3+31*e4/180+517*e6/5040)*math.Sin(2*xi) +
+		(23*e4/360+251*e6/3780)*math.Sin(4*xi) +
+		761*e6/45360*math.Sin(6*xi)
+	q := math.Sin(xi) * e.authalicQ(pi/2)
+	for i := 0; i < 5; i++ {
+		s, c := math.Sin(phi), math.Cos(phi)
+		dq := 2 * c / sq(1-e2*s*s) // dq/dphi
+		if dq == 0 {
+			break
+		}
+		dphi := (q - e.authalicQ(phi)) / dq
+		phi += dphi
+		if !(math.Abs(dphi) >= 1e-15) {
+			break
+		}
+	}
+	return phi
+}
+
+// angleIn converts an angle in the Units of the Ellipsoid to radians.
+func (ellipsoid Ellipsoid) angleIn(x float64) float64 {
+	if ellipsoid.Units == Degrees {
+		return deg2rad(x)
+	}
+	return x
+}
+
+// angleOut converts an angle in radians to the Units of the Ellipsoid.
+func (ellipsoid Ellipsoid) angleOut(x float64) float64 {
+	if ellipsoid.Units == Degrees {
+		return rad2deg(x)
+	}
+	return x
+}
+
+/*
+ToGeocentricLatitude returns the geocentric latitude of the geodetic
+latitude lat: the angle between the equator and the line from the centre
+of the earth to the point.
+
+	theta := geo.ToGeocentricLatitude(lat)
+*/
+func (ellipsoid Ellipsoid) ToGeocentricLatitude(lat float64) float64 {
+	phi := ellipsoid.angleIn(lat)
+	e2m := 1 - ellipsoid.Ellipse.eccentricitySquared()
+	return ellipsoid.angleOut(math.Atan2(e2m*math.Sin(phi), math.Cos(phi)))
+}
+
+// FromGeocentricLatitude is the inverse of ToGeocentricLatitude.
+func (ellipsoid Ellipsoid) FromGeocentricLatitude(theta float64) float64 {
+	x := ellipsoid.angleIn(theta)
+	e2m := 1 - ellipsoid.Ellipse.eccentricitySquared()
+	return ellipsoid.angleOut(math.Atan2(math.Sin(x), e2m*math.Cos(x)))
+}
+
+/*
+ToParametricLatitude returns the parametric or reduced latitude of the
+geodetic latitude lat: the latitude on the circumscribed sphere of the
+point projected parallel to the axis.
+
+	beta := geo.ToParametricLatitude(lat)
+*/
+func (ellipsoid Ellipsoid) ToParametricLatitude(lat float64) float64 {
+	phi := ellipsoid.angleIn(lat)
+	f1 := 1 - ellipsoid.Ellipse.flattening()
+	return ellipsoid.angleOut(math.Atan2(f1*math.Sin(phi), math.Cos(phi)))
+}
+
+// FromParametricLatitude is the inverse of ToParametricLatitude.
+func (ellipsoid Ellipsoid) FromParametricLatitude(beta float64) float64 {
+	x := ellipsoid.angleIn(beta)
+	f1 := 1 - ellipsoid.Ellipse.flattening()
+	return ellipsoid.angleOut(math.Atan2(math.Sin(x), f1*math.Cos(x)))
+}
+
+/*
+ToConformalLatitude returns the conformal latitude of the geodetic
+latitude lat, which maps the ellipsoid conformally onto a sphere.
+
+	chi := geo.ToConformalLatitude(lat)
+*/
+func (ellipsoid Ellipsoid) ToConformalLatitude(lat float64) float64 {
+	phi := ellipsoid.angleIn(lat)
+	if math.Abs(phi) == pi/2 {
+		return lat
+	}
+	taup := taupf(math.Tan(phi), ellipsoid.Ellipse.eccentricity())
+	return ellipsoid.angleOut(math.Atan(taup))
+}
+
+// FromConformalLatitude is the inverse of ToConformalLatitude.
+func (ellipsoid Ellipsoid) FromConformalLatitude(chi float64) float64 {
+	x := ellipsoid.angleIn(chi)
+	if math.Abs(x) == pi/2 {
+		return chi
+	}
+	tau := tauf(math.Tan(x), ellipsoid.Ellipse.eccentricity())
+	return ellipsoid.angleOut(math.Atan(tau))
+}
+
+/*
+ToAuthalicLatitude returns the authalic latitude of the geodetic latitude
+lat, which maps the ellipsoid onto the sphere of the same area so that
+areas are preserved.
+
+	xi := geo.ToAuthalicLatitude(lat)
+*/
+func (ellipsoid Ellipsoid) ToAuthalicLatitude(lat float64) float64 {
+	return ellipsoid.angleOut(ellipsoid.Ellipse.authalic(ellipsoid.angleIn(lat)))
+}
+
+// FromAuthalicLatitude is the inverse of ToAuthalicLatitude.
+func (ellipsoid Ellipsoid) FromAuthalicLatitude(xi float64) float64 {
+	return ellipsoid.angleOut(ellipsoid.Ellipse.fromAuthalic(ellipsoid.angleIn(xi)))
+}
+
+/*
+ToRectifyingLatitude returns the rectifying latitude of the geodetic
+latitude lat, which is proportional to the distance along the meridian
+from the equator.
+
+	mu := geo.ToRectifyingLatitude(lat)
+*/
+func (ellipsoid Ellipsoid) ToRectifyingLatitude(lat float64) float64 {
+	e := ellipsoid.Ellipse
+	mu := pi / 2 * e.meridianArc(ellipsoid.angleIn(lat)) / e.meridianArc(pi/2)
+	return ellipsoid.angleOut(mu)
+}
+
+// FromRectifyingLatitude is the inverse of ToRectifyingLatitude.
+func (ellipsoid Ellipsoid) FromRectifyingLatitude(mu float64) float64 {
+	e := ellipsoid.Ellipse
+	m := ellipsoid.angleIn(mu) / (pi / 2) * e.meridianArc(pi/2)
+	return ellipsoid.angleOut(e.footpointLatitude(m))
+}
+
+/*
+ToIsometricLatitude returns the isometric latitude of the geodetic
+latitude lat, the ordinate of the Mercator projection of the unit
+sphere. Unlike the other auxiliary latitudes it is not an angle and so
+it is not in the Units of the ellipsoid. It is infinite at the poles.
+
+	psi := geo.ToIsometricLatitude(lat)
+*/
+func (ellipsoid Ellipsoid) ToIsometricLatitude(lat float64) float64 {
+	return ellipsoid.Ellipse.isometricLatitude(ellipsoid.angleIn(lat))
+}
+
+// FromIsometricLatitude is the inverse of ToIsometricLatitude.
+func (ellipsoid Ellipsoid) FromIsometricLatitude(psi float64) float64 {
+	tau := tauf(math.Sinh(psi), ellipsoid.Ellipse.eccentricity())
+	return ellipsoid.angleOut(math.Atan(tau))
+}
@@ -0,0 +1,79 @@
+package ellipsoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAuxiliaryLatitudes(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+	k, _ := New("WGS84", WithGeodesicSolver(Karney))
+	a := e.Ellipse.Equatorial
+	f := e.Ellipse.flattening()
+	b := a * (1 - f)
+	e2 := e.Ellipse.eccentricitySquared()
+
+	// area between the equator and the parallel phi, over 2 pi
+	zone := func(phi float64) float64 {
+		s := math.Sin(phi)
+		ecc := math.Sqrt(e2)
+		return b * b * (s/(2*(1-e2*s*s)) + math.Atanh(ecc*s)/(2*ecc))
+	}
+
+	for lat := -90.0; lat <= 90; lat += 7.5 {
+		phi := lat * degree
+
+		// The geocentric latitude is the direction of the ECEF vector,
+		// the parametric one that of the point scaled onto the sphere.
+		x, _, z := e.ToECEF(lat, 0, 0)
+		deltaWithin(t, loc(), e.ToGeocentricLatitude(lat), rad2deg(math.Atan2(z, x)), 1e-12)
+		deltaWithin(t, loc(), e.ToParametricLatitude(lat), rad2deg(math.Atan2(z/b, x/a)), 1e-12)
+
+		// The conformal latitude is the Gudermannian of the isometric one.
+		if math.Abs(lat) < 90 {
+			psi := e.ToIsometricLatitude(lat)
+			deltaWithin(t, loc(), e.ToConformalLatitude(lat), rad2deg(math.Atan(math.Sinh(psi))), 1e-12)
+			deltaWithin(t, loc(), e.FromIsometricLatitude(psi), lat, 1e-12)
+		}
+
+		deltaWithin(t, loc(), math.Sin(e.ToAuthalicLatitude(lat)*degree), zone(phi)/zone(pi/2), 1e-14)
+
+		want, _ := k.To(0, 0, lat, 0)
+		meridian, _ := k.To(0, 0, 90, 0)
+		deltaWithin(t, loc(), e.ToRectifyingLatitude(lat), 90*math.Copysign(want, lat)/meridian, 1e-11)
+
+		for _, c := range []struct {
+			to, from func(float64) float64
+		}{
+			{e.ToGeocentricLatitude, e.FromGeocentricLatitude},
+			{e.ToParametricLatitude, e.FromParametricLatitude},
+			{e.ToConformalLatitude, e.FromConformalLatitude},
+			{e.ToAuthalicLatitude, e.FromAuthalicLatitude},
+			{e.ToRectifyingLatitude, e.FromRectifyingLatitude},
+		} {
+			deltaWithin(t, loc(), c.from(c.to(lat)), lat, 1e-11)
+		}
+	}
+
+	// tan(theta) = (1 - f)^2 tan(phi) and tan(beta) = (1 - f) tan(phi).
+	deltaWithin(t, loc(), math.Tan(e.ToGeocentricLatitude(45)*degree), sq(1-f), 1e-15)
+	deltaWithin(t, loc(), math.Tan(e.ToParametricLatitude(45)*degree), 1-f, 1e-15)
+
+	if psi := e.ToIsometricLatitude(90); !math.IsInf(psi, 1) {
+		t.Errorf("ToIsometricLatitude(90) = %v", psi)
+	}
+	deltaWithin(t, loc(), e.FromIsometricLatitude(math.Inf(-1)), -90, 1e-12)
+
+	// On a sphere all the latitudes agree.
+	s, _ := NewCustom(6371000, math.Inf(1), WithUnits(Radians))
+	for _, c := range []func(float64) float64{
+		s.ToGeocentricLatitude, s.FromGeocentricLatitude,
+		s.ToParametricLatitude, s.FromParametricLatitude,
+		s.ToConformalLatitude, s.FromConformalLatitude,
+		s.ToAuthalicLatitude, s.FromAuthalicLatitude,
+		s.ToRectifyingLatitude, s.FromRectifyingLatitude,
+	} {
+		deltaWithin(t, loc(), c(0.7), 0.7, 1e-15)
+	}
+	deltaWithin(t, loc(), s.ToIsometricLatitude(0.7), math.Asinh(math.Tan(0.7)), 1e-15)
+}
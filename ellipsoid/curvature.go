@@ -0,0 +1,93 @@
+package ellipsoid
+
+import "math"
+
+// meridionalRadius returns the radius of curvature of the meridian M in
+// meters at the latitude phi in radians.
+func (e ellipse) meridionalRadius(phi float64) float64 {
+	e2 := e.eccentricitySquared()
+	w2 := 1 - e2*sq(math.Sin(phi))
+	return e.Equatorial * (1 - e2) / (w2 * math.Sqrt(w2))
+}
+
+// primeVerticalRadius returns the radius of curvature in the prime
+// vertical N in meters at the latitude phi in radians.
+func (e ellipse) primeVerticalRadius(phi float64) float64 {
+	return e.Equatorial / math.Sqrt(1-e.eccentricitySquared()*sq(math.Sin(phi)))
+}
+
+/*
+MeridionalRadius returns the radius of curvature M of the meridian at the
+latitude lat in the distance units.
+
+	m := geo.MeridionalRadius(lat)
+*/
+func (ellipsoid Ellipsoid) MeridionalRadius(lat float64) float64 {
+	return ellipsoid.Ellipse.meridionalRadius(ellipsoid.angleIn(lat)) / ellipsoid.DistanceFactor
+}
+
+/*
+PrimeVerticalRadius returns the radius of curvature N in the prime
+vertical, perpendicular to the meridian, at the latitude lat in the
+distance units.
+
+	n := geo.PrimeVerticalRadius(lat)
+*/
+func (ellipsoid Ellipsoid) PrimeVerticalRadius(lat float64) float64 {
+	return ellipsoid.Ellipse.primeVerticalRadius(ellipsoid.angleIn(lat)) / ellipsoid.DistanceFactor
+}
+
+/*
+GaussianRadius returns the Gaussian mean radius sqrt(M N) at the latitude
+lat in the distance units, the radius of the sphere that best fits the
+ellipsoid there.
+
+	r := geo.GaussianRadius(lat)
+*/
+func (ellipsoid Ellipsoid) GaussianRadius(lat float64) float64 {
+	phi := ellipsoid.angleIn(lat)
+	e := ellipsoid.Ellipse
+	return math.Sqrt(e.meridionalRadius(phi)*e.primeVerticalRadius(phi)) / ellipsoid.DistanceFactor
+}
+
+/*
+RadiusInAzimuth returns the radius of curvature of the normal section in
+the direction bearing at the latitude lat in the distance units. It is
+M along the meridian and N across it (Euler's formula).
+
+	r := geo.RadiusInAzimuth(lat, bearing)
+*/
+func (ellipsoid Ellipsoid) RadiusInAzimuth(lat, bearing float64) float64 {
+	phi := ellipsoid.angleIn(lat)
+	alpha := ellipsoid.angleIn(bearing)
+	e := ellipsoid.Ellipse
+	m, n := e.meridionalRadius(phi), e.primeVerticalRadius(phi)
+	return m * n / (n*sq(math.Cos(alpha)) + m*sq(math.Sin(alpha))) / ellipsoid.DistanceFactor
+}
+
+/*
+MeridianArc returns the distance along the meridian from the equator to
+the latitude lat in the distance units. It is negative in the southern
+hemisphere.
+
+	dist := geo.MeridianArc(lat)
+*/
+func (ellipsoid Ellipsoid) MeridianArc(lat float64) float64 {
+	return ellipsoid.Ellipse.meridianArc(ellipsoid.angleIn(lat)) / ellipsoid.DistanceFactor
+}
+
+/*
+FromMeridianArc is the inverse of MeridianArc. It returns the latitude at
+the distance along the meridian from the equator, or NaN if the distance
+is longer than the quarter meridian.
+
+	lat := geo.FromMeridianArc(dist)
+*/
+func (ellipsoid Ellipsoid) FromMeridianArc(distance float64) float64 {
+	e := ellipsoid.Ellipse
+	m := distance * ellipsoid.DistanceFactor
+	if math.Abs(m) > e.meridianArc(pi/2) {
+		return math.NaN()
+	}
+	return ellipsoid.angleOut(e.footpointLatitude(m))
+}
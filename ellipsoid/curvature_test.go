@@ -0,0 +1,55 @@
+package ellipsoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRadiiOfCurvature(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+	a := e.Ellipse.Equatorial
+	b := e.Ellipse.polar()
+
+	deltaWithin(t, loc(), e.MeridionalRadius(0), b*b/a, 1e-6)
+	deltaWithin(t, loc(), e.PrimeVerticalRadius(0), a, 1e-6)
+	deltaWithin(t, loc(), e.MeridionalRadius(90), a*a/b, 1e-6)
+	deltaWithin(t, loc(), e.PrimeVerticalRadius(-90), a*a/b, 1e-6)
+	deltaWithin(t, loc(), e.MeridionalRadius(45), 6367381.816, 1e-3)
+	deltaWithin(t, loc(), e.PrimeVerticalRadius(45), 6388838.290, 1e-3)
+
+	for _, lat := range []float64{-60, 0, 30, 89} {
+		m, n := e.MeridionalRadius(lat), e.PrimeVerticalRadius(lat)
+		deltaWithin(t, loc(), e.GaussianRadius(lat), math.Sqrt(m*n), 1e-6)
+		deltaWithin(t, loc(), e.RadiusInAzimuth(lat, 0), m, 1e-6)
+		deltaWithin(t, loc(), e.RadiusInAzimuth(lat, 180), m, 1e-6)
+		deltaWithin(t, loc(), e.RadiusInAzimuth(lat, 90), n, 1e-6)
+		deltaWithin(t, loc(), e.RadiusInAzimuth(lat, 270), n, 1e-6)
+		r := e.RadiusInAzimuth(lat, 45)
+		if !(r > m && r < n) {
+			t.Errorf("RadiusInAzimuth(%v, 45) = %v not between %v and %v", lat, r, m, n)
+		}
+	}
+
+	r := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	deltaWithin(t, loc(), r.MeridionalRadius(45*degree), 6367.381816, 1e-6)
+	deltaWithin(t, loc(), r.RadiusInAzimuth(30*degree, pi/2), e.PrimeVerticalRadius(30)/1000, 1e-9)
+}
+
+func TestMeridianArc(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+	k, _ := New("WGS84", WithGeodesicSolver(Karney))
+
+	deltaWithin(t, loc(), e.MeridianArc(90), 10001965.729, 1e-3)
+	for lat := -90.0; lat <= 90; lat += 10 {
+		want, _ := k.To(0, 0, lat, 0)
+		deltaWithin(t, loc(), e.MeridianArc(lat), math.Copysign(want, lat), 1e-6)
+		deltaWithin(t, loc(), e.FromMeridianArc(e.MeridianArc(lat)), lat, 1e-11)
+	}
+	if lat := e.FromMeridianArc(10002e3); !math.IsNaN(lat) {
+		t.Errorf("FromMeridianArc beyond the pole = %v", lat)
+	}
+
+	m := Init("WGS84", Radians, Mile, LongitudeIsSymmetric, BearingNotSymmetric)
+	deltaWithin(t, loc(), m.MeridianArc(pi/4), e.MeridianArc(45)/1609.344, 1e-9)
+	deltaWithin(t, loc(), m.FromMeridianArc(1000), e.FromMeridianArc(1609344)*degree, 1e-14)
+}
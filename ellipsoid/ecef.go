@@ -0,0 +1,139 @@
+package ellipsoid
+
+import "math"
+
+// vermeille returns latitude and longitude in radians and the elevation
+// in meters of the point x, y, z in meters. It is the closed form
+// solution of
+//
+//	H. Vermeille, An analytical method to transform geocentric into
+//	geodetic coordinates, J. Geodesy 85, 105-117 (2011),
+//	https://doi.org/10.1007/s00190-010-0419-x
+//
+// as implemented in GeographicLib by Charles Karney (MIT/X11 License),
+// which extends it to the inside of the ellipsoid and to prolate
+// ellipsoids. The centre of the earth maps to the north pole.
+func (e ellipse) vermeille(x, y, z float64) (phi, lambda, h float64) {
+	a := e.Equatorial
+	f := e.flattening()
+	e2 := e.eccentricitySquared()
+	e2m := sq(1 - f)
+	e2a := math.Abs(e2)
+	e4a := sq(e2)
+	maxrad := 2 * a / dblEpsilon
+
+	R := math.Hypot(x, y)
+	slam, clam := 0.0, 1.0
+	if R != 0 {
+		slam, clam = y/R, x/R
+	}
+	h = math.Hypot(R, z) // distance to the centre of the earth
+	var sphi, cphi float64
+	switch {
+	case h > maxrad:
+		// Really far away; treat the earth as a point and h as the
+		// height. Scale by 2 to avoid overflow of R.
+		R = math.Hypot(x/2, y/2)
+		slam, clam = 0.0, 1.0
+		if R != 0 {
+			slam, clam = (y/2)/R, (x/2)/R
+		}
+		H := math.Hypot(z/2, R)
+		sphi, cphi = (z/2)/H, R/H
+	case e4a == 0:
+		// The sphere. The centre maps to the north pole.
+		zz := z
+		if h == 0 {
+			zz = 1
+		}
+		H := math.Hypot(zz, R)
+		sphi, cphi = zz/H, R/H
+		h -= a
+	default:
+		// Treat prolate spheroids by swapping R and z here and by
+		// switching the arguments to phi = atan2(...) at the end.
+		p := sq(R / a)
+		q := e2m * sq(z/a)
+		r := (p + q - e4a) / 6
+		if f < 0 {
+			p, q = q, p
+		}
+		if !(e4a*q == 0 && r <= 0) {
+			// Avoid possible division by zero when r = 0 by multiplying
+			// equations for s and t by r^3 and r, resp.
+			S := e4a * p * q / 4 // S = r^3 * s
+			r2 := sq(r)
+			r3 := r * r2
+			disc := S * (2*r3 + S)
+			u := r
+			if disc >= 0 {
+				T3 := S + r3
+				// Pick the sign on the sqrt to maximize abs(T3). This
+				// minimizes loss of precision due to cancellation.
+				if T3 < 0 {
+					T3 -= math.Sqrt(disc)
+				} else {
+					T3 += math.Sqrt(disc)
+				} // T3 = (r * t)^3
+				T := math.Cbrt(T3) // T = r * t
+				// T can be zero; but then r2 / T -> 0.
+				u += T
+				if T != 0 {
+					u += r2 / T
+				}
+			} else {
+				// T is complex, but the way u is defined the result is
+				// real. Choose the cube root that avoids cancellation.
+				ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+				u += 2 * r * math.Cos(ang/3)
+			}
+			v := math.Sqrt(sq(u) + e4a*q) // guaranteed positive
+			// Avoid loss of accuracy when u < 0.
+			var uv float64 // u+v, guaranteed positive
+			if u < 0 {
+				uv = e4a * q / (v - u)
+			} else {
+				uv = u + v
+			}
+			// Guard against w going negative due to roundoff in uv - q.
+			w := math.Max(0, e2a*(uv-q)/(2*v))
+			// Rearrange expression for k to avoid loss of accuracy due
+			// to subtraction. Division by 0 is not possible because
+			// uv > 0, w >= 0.
+			k := uv / (math.Sqrt(uv+sq(w)) + w)
+			k1, k2 := k, k+e2
+			if f < 0 {
+				k1, k2 = k-e2, k
+			}
+			d := k1 * R / k2
+			H := math.Hypot(z/k1, R/k2)
+			sphi, cphi = (z/k1)/H, (R/k2)/H
+			h = (1 - e2m/k1) * math.Hypot(d, z)
+		} else {
+			// e4 * q == 0 && r <= 0. This leads to k = 0 (oblate,
+			// equatorial plane) and k + e^2 = 0 (prolate, rotation
+			// axis), so take the limits:
+			// f > 0: z -> 0, k      ->   e2 * sqrt(q)/sqrt(e4 - p)
+			// f < 0: R -> 0, k + e2 -> - e2 * sqrt(q)/sqrt(e4 - p)
+			var zz, xx float64
+			if f >= 0 {
+				zz = math.Sqrt((e4a - p) / e2m)
+				xx = math.Sqrt(p)
+			} else {
+				zz = math.Sqrt(p / e2m)
+				xx = math.Sqrt(e4a - p)
+			}
+			H := math.Hypot(zz, xx)
+			sphi, cphi = zz/H, xx/H
+			if z < 0 {
+				sphi = -sphi // for tiny negative z (not for prolate)
+			}
+			if f >= 0 {
+				h = -a * e2m * H / e2a
+			} else {
+				h = -a * H / e2a
+			}
+		}
+	}
+	return math.Atan2(sphi, cphi), math.Atan2(slam, clam), h
+}
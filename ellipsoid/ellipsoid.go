@@ -75,6 +75,30 @@ const (
 	BearingNotSymmetric = false
 )
 
+// The solvers for the geodesic problems of To and At.
+const (
+	// Vincenty selects the iterative method of Vincenty, as in
+	// Geo::Ellipsoid. It is fast, but it does not converge for nearly
+	// antipodal points.
+	Vincenty = iota
+	// Karney selects the method of Karney, which is accurate to a few
+	// nanometers and converges for all pairs of points.
+	Karney
+)
+
+// The solvers for the conversion from ECEF to geodetic coordinates of
+// ToLLA.
+const (
+	// Bowring selects the single step approximation of Bowring. It is
+	// accurate to a millimeter near the surface of the earth, but it
+	// degrades far from it.
+	Bowring = iota
+	// Vermeille selects the closed form solution of Vermeille, which is
+	// exact for all points including the poles and the centre of the
+	// earth.
+	Vermeille
+)
+
 // Ellipsoid is the main object to store information about one ellispoid.
 type Ellipsoid struct {
 	Ellipse            ellipse
@@ -83,6 +107,8 @@ type Ellipsoid struct {
 	LongitudeSymmetric bool
 	BearingSymmetry    bool
 	DistanceFactor     float64
+	GeodesicSolver     int
+	ECEFSolver         int
 	// Having the DistanceFactor AND the DistanceUnits in this struct is redundant
 	// but it looks nicer in the code.
 }
@@ -106,14 +132,19 @@ func rad2deg(d float64) (r float64) {
 	return d * 180.0 / pi
 }
 
+// conversion holds the length of one distance unit in meters,
+// indexed by Meter, Foot, Kilometer, Mile and Nm.
+var conversion = []float64{1.0, 0.3048, 1000.0, 1609.344, 1852.0}
+
 /* Init
 
 The Init constructor must be called with a list of parameters to set
 the value of the ellipsoid to be used, the value of the units to be
 used for angles and distances, and whether or not the output range
 of longitudes and bearing angles should be symmetric around zero
-or always greater than zero. There is no default constructor, all
-arguments are required; they may not be abbreviated.
+or always greater than zero. All arguments are required; they may
+not be abbreviated. Init prints a warning for an unknown ellipsoid
+or an invalid unit; use New to get an error instead.
 
 Example:
 
@@ -132,49 +163,192 @@ Example:
 
 */
 func Init(name string, units int, distUnits int, longSym bool, bearSym bool) (e Ellipsoid) {
-	m := map[string]ellipse{
-		"AIRY":                  {6377563.396, 299.3249646},
-		"AIRY-MODIFIED":         {6377340.189, 299.3249646},
-		"AUSTRALIAN":            {6378160.0, 298.25},
-		"BESSEL-1841":           {6377397.155, 299.1528128},
-		"BESSEL-1841-NAMIBIA":   {6377483.865, 299.152813},
-		"CLARKE-1866":           {6378206.400, 294.978698},
-		"CLARKE-1880":           {6378249.145, 293.465},
-		"EVEREST-1830":          {6377276.345, 300.8017},
-		"EVEREST-1948":          {6377304.063, 300.8017},
-		"EVEREST-SABAH-SARAWAK": {6377298.556, 300.801700},
-		"EVEREST-1956":          {6377301.243, 300.801700},
-		"EVEREST-1969":          {6377295.664, 300.801700},
-		"FISHER-1960":           {6378166.0, 298.3},
-		"FISCHER-1960-MODIFIED": {6378155.000, 298.300000},
-		"FISHER-1968":           {6378150.0, 298.3},
-		"GRS80":                 {6378137.0, 298.25722210088},
-		"HELMERT-1906":          {6378200.000, 298.300000},
-		"HOUGH-1956":            {6378270.0, 297.0},
-		"HAYFORD":               {6378388.0, 297.0},
-		"IAU76":                 {6378140.0, 298.257},
-		"INTERNATIONAL":         {6378388.000, 297.000000},
-		"KRASSOVSKY-1938":       {6378245.0, 298.3},
-		"NAD27":                 {6378206.4, 294.9786982138},
-		"NWL-9D":                {6378145.0, 298.25},
-		"SGS85":                 {6378136.000, 298.257000},
-		"SOUTHAMERICAN-1969":    {6378160.0, 298.25},
-		"SOVIET-1985":           {6378136.0, 298.257},
-		"WGS60":                 {6378165.000, 298.300000},
-		"WGS66":                 {6378145.000, 298.250000},
-		"WGS72":                 {6378135.0, 298.26},
-		"WGS84":                 {6378137.0, 298.257223563},
-	}
-
-	e2, ok := m[name]
+	e, err := New(name,
+		WithUnits(units),
+		WithDistanceUnits(distUnits),
+		WithLongitudeSymmetry(longSym),
+		WithBearingSymmetry(bearSym))
+	if err != nil {
+		fmt.Printf("ellipsoid.go: Warning: %v\n", err)
+		// Keep the requested settings, as Init always did.
+		e2, _ := lookup(name)
+		e = Ellipsoid{
+			Ellipse:            e2,
+			Units:              units,
+			DistanceUnits:      distUnits,
+			LongitudeSymmetric: longSym,
+			BearingSymmetry:    bearSym,
+		}
+		if distUnits < 0 || distUnits >= len(conversion) {
+			fmt.Printf("ellipsoid.go: Warning: using Meter instead of distance unit %d\n", distUnits)
+			e.DistanceUnits = Meter
+		}
+		e.DistanceFactor = conversion[e.DistanceUnits]
+	}
+	return e
+}
+
+/*
+New returns the ellipsoid with the given name, configured by the
+options. The name is looked up in the registry, see Lookup. Unlike
+Init, New reports an unknown ellipsoid or an invalid unit as an error
+of type *UnknownEllipsoidError or *InvalidUnitError, so that a bad
+configuration can be rejected at startup.
+
+Without options the angle units are Degrees, the distance units are
+Meter, both longitude and bearing are symmetric, the geodesic solver is
+Vincenty and the ECEF solver is Bowring.
+
+Example:
+
+	geo, err := ellipsoid.New("WGS84",
+		ellipsoid.WithUnits(ellipsoid.Radians),
+		ellipsoid.WithDistanceUnits(ellipsoid.Kilometer),
+		ellipsoid.WithBearingSymmetry(ellipsoid.BearingNotSymmetric))
+	if err != nil {
+		log.Fatal(err)
+	}
+*/
+func New(name string, opts ...Option) (Ellipsoid, error) {
+	e2, ok := lookup(name)
 	if !ok {
-		fmt.Printf("ellipsoid.go: Warning: Invalid ellipse type '%v'\n", name)
+		return Ellipsoid{}, &UnknownEllipsoidError{Name: name}
+	}
+	return newEllipsoid(e2, opts)
+}
+
+/*
+NewCustom returns an ellipsoid that is not in the list of predefined
+ellipsoids, given its semi-major axis in meters and its inverse
+flattening. An inverse flattening of 0 or +Inf defines a sphere.
+
+Example:
+
+	// CGCS2000, the China Geodetic Coordinate System 2000
+	geo, err := ellipsoid.NewCustom(6378137.0, 298.257222101)
+*/
+func NewCustom(equatorial, invFlattening float64, opts ...Option) (Ellipsoid, error) {
+	e2, err := newEllipse(equatorial, invFlattening)
+	if err != nil {
+		return Ellipsoid{}, err
+	}
+	return newEllipsoid(e2, opts)
+}
+
+// newEllipse validates the defining parameters of an ellipse.
+func newEllipse(equatorial, invFlattening float64) (ellipse, error) {
+	if !(equatorial > 0) || math.IsInf(equatorial, 0) {
+		return ellipse{}, &InvalidEllipseError{Param: "semi-major axis", Value: equatorial}
+	}
+	if invFlattening == 0 {
+		invFlattening = math.Inf(1)
+	}
+	if !(invFlattening > 1) {
+		return ellipse{}, &InvalidEllipseError{Param: "inverse flattening", Value: invFlattening}
+	}
+	return ellipse{equatorial, invFlattening}, nil
+}
+
+// NewFromSemiMinor returns a custom ellipsoid given its semi-major and
+// semi-minor axes in meters. Equal axes define a sphere.
+func NewFromSemiMinor(equatorial, polar float64, opts ...Option) (Ellipsoid, error) {
+	if !(polar > 0) || polar > equatorial {
+		return Ellipsoid{}, &InvalidEllipseError{Param: "semi-minor axis", Value: polar}
+	}
+	f := (equatorial - polar) / equatorial
+	return NewCustom(equatorial, 1/f, opts...)
+}
+
+// NewFromEccentricitySquared returns a custom ellipsoid given its
+// semi-major axis in meters and the square of its first eccentricity.
+// An eccentricity of 0 defines a sphere.
+func NewFromEccentricitySquared(equatorial, esq float64, opts ...Option) (Ellipsoid, error) {
+	if !(esq >= 0 && esq < 1) {
+		return Ellipsoid{}, &InvalidEllipseError{Param: "eccentricity squared", Value: esq}
+	}
+	// f = 1 - sqrt(1 - e^2), written to avoid cancellation for small e^2.
+	f := esq / (1 + math.Sqrt(1-esq))
+	return NewCustom(equatorial, 1/f, opts...)
+}
+
+// newEllipsoid applies the options to an ellipsoid with the
+// default settings and validates the resulting units.
+func newEllipsoid(e2 ellipse, opts []Option) (Ellipsoid, error) {
+	ellipsoid := Ellipsoid{
+		Ellipse:            e2,
+		Units:              Degrees,
+		DistanceUnits:      Meter,
+		LongitudeSymmetric: LongitudeIsSymmetric,
+		BearingSymmetry:    BearingIsSymmetric,
+	}
+	for _, opt := range opts {
+		opt(&ellipsoid)
+	}
+
+	if ellipsoid.Units != Degrees && ellipsoid.Units != Radians {
+		return Ellipsoid{}, &InvalidUnitError{Kind: "angle", Unit: ellipsoid.Units}
+	}
+	if ellipsoid.DistanceUnits < 0 || ellipsoid.DistanceUnits >= len(conversion) {
+		return Ellipsoid{}, &InvalidUnitError{Kind: "distance", Unit: ellipsoid.DistanceUnits}
+	}
+	if ellipsoid.GeodesicSolver != Vincenty && ellipsoid.GeodesicSolver != Karney {
+		return Ellipsoid{}, &InvalidSolverError{Kind: "geodesic", Solver: ellipsoid.GeodesicSolver}
+	}
+	if ellipsoid.ECEFSolver != Bowring && ellipsoid.ECEFSolver != Vermeille {
+		return Ellipsoid{}, &InvalidSolverError{Kind: "ECEF", Solver: ellipsoid.ECEFSolver}
+	}
+	ellipsoid.DistanceFactor = conversion[ellipsoid.DistanceUnits]
+	return ellipsoid, nil
+}
+
+// Option configures an Ellipsoid created by New.
+type Option func(*Ellipsoid)
+
+// WithUnits sets the angle units, Degrees or Radians.
+func WithUnits(units int) Option {
+	return func(e *Ellipsoid) {
+		e.Units = units
+	}
+}
+
+// WithDistanceUnits sets the distance units, one of Meter, Foot,
+// Kilometer, Mile or Nm.
+func WithDistanceUnits(distUnits int) Option {
+	return func(e *Ellipsoid) {
+		e.DistanceUnits = distUnits
 	}
+}
+
+// WithLongitudeSymmetry sets whether output longitudes are symmetric,
+// LongitudeIsSymmetric or LongitudeNotSymmetric.
+func WithLongitudeSymmetry(longSym bool) Option {
+	return func(e *Ellipsoid) {
+		e.LongitudeSymmetric = longSym
+	}
+}
 
-	//                      m    ft      km      mi        nm
-	conversion := []float64{1.0, 0.3048, 1000.0, 1609.344, 1852.0}
-	ellipsoid := Ellipsoid{e2, units, distUnits, longSym, bearSym, conversion[distUnits]}
-	return ellipsoid
+// WithGeodesicSolver selects the solver used by To and At, Vincenty
+// (the default) or Karney.
+func WithGeodesicSolver(solver int) Option {
+	return func(e *Ellipsoid) {
+		e.GeodesicSolver = solver
+	}
+}
+
+// WithECEFSolver selects the solver used by ToLLA, Bowring (the
+// default) or Vermeille.
+func WithECEFSolver(solver int) Option {
+	return func(e *Ellipsoid) {
+		e.ECEFSolver = solver
+	}
+}
+
+// WithBearingSymmetry sets whether output bearings are symmetric,
+// BearingIsSymmetric or BearingNotSymmetric.
+func WithBearingSymmetry(bearSym bool) Option {
+	return func(e *Ellipsoid) {
+		e.BearingSymmetry = bearSym
+	}
 }
 
 /* Intermediate
@@ -216,12 +390,112 @@ func (ellipsoid Ellipsoid) Intermediate(lat1, lon1, lat2, lon2 float64, steps in
 
 }
 
+// Constants for the endpoints argument of IntermediateLocations and
+// Densify.
+const (
+	IncludeEndpoints = true
+	ExcludeEndpoints = false
+)
+
+/*
+IntermediateLocations is Intermediate returning the points as Locations.
+If endpoints is IncludeEndpoints, the first and the last Location are the
+two given points, with ExcludeEndpoints only the steps-1 points in
+between are returned.
+
+	distance, bearing, locs := geo.IntermediateLocations(lat1, lon1, lat2, lon2, 4, ellipsoid.IncludeEndpoints)
+
+steps shall be positive, otherwise no Locations are returned.
+*/
+func (ellipsoid Ellipsoid) IntermediateLocations(lat1, lon1, lat2, lon2 float64, steps int, endpoints bool) (distance, bearing float64, locs []Location) {
+	if steps <= 0 {
+		return
+	}
+	distance, bearing = ellipsoid.To(lat1, lon1, lat2, lon2)
+	return distance, bearing, ellipsoid.intermediate(lat1, lon1, lat2, lon2, distance, bearing, steps, endpoints)
+}
+
+// maxDensifySteps bounds the number of points Densify creates.
+const maxDensifySteps = 1 << 20
+
+/*
+Densify returns range and bearing and the points on the geodesic between
+two points such that neighbouring points are no more than spacing apart,
+in the distance units of the ellipsoid. The points are equally spaced.
+endpoints is IncludeEndpoints or ExcludeEndpoints as for
+IntermediateLocations.
+
+	distance, bearing, locs := geo.Densify(lat1, lon1, lat2, lon2, 5, ellipsoid.IncludeEndpoints)
+
+spacing shall be positive, otherwise no Locations are returned. No
+Locations are returned either if they would be more than about a
+million.
+*/
+func (ellipsoid Ellipsoid) Densify(lat1, lon1, lat2, lon2, spacing float64, endpoints bool) (distance, bearing float64, locs []Location) {
+	if !(spacing > 0) {
+		return
+	}
+	distance, bearing = ellipsoid.To(lat1, lon1, lat2, lon2)
+	ratio := math.Ceil(distance / spacing)
+	if !(ratio <= maxDensifySteps) {
+		return
+	}
+	steps := int(ratio)
+	if steps == 0 {
+		steps = 1
+	}
+	return distance, bearing, ellipsoid.intermediate(lat1, lon1, lat2, lon2, distance, bearing, steps, endpoints)
+}
+
+// intermediate returns steps-1 equally spaced points on the geodesic
+// of the given range and bearing between two points, plus the two
+// points if endpoints is set. The positions are computed with At for
+// the Vincenty solver and taken from a GeodesicLine with the bearing
+// for the Karney solver.
+func (ellipsoid Ellipsoid) intermediate(lat1, lon1, lat2, lon2, distance, bearing float64, steps int, endpoints bool) (locs []Location) {
+
+	position := func(s float64) (lat, lon float64) {
+		return ellipsoid.At(lat1, lon1, s, bearing)
+	}
+	if ellipsoid.GeodesicSolver == Karney {
+		line := ellipsoid.Line(lat1, lon1, bearing)
+		position = func(s float64) (lat, lon float64) {
+			lat, lon, _ = line.Position(s)
+			return
+		}
+	}
+
+	half := 180.0
+	if ellipsoid.Units == Radians {
+		half = pi
+	}
+
+	locs = make([]Location, 0, steps+1)
+	if endpoints {
+		locs = append(locs, Location{Lat: lat1, Lon: ellipsoid.adjustLongitude(lon1, half)})
+	}
+	for i := 1; i < steps; i++ {
+		lat, lon := position(distance * float64(i) / float64(steps))
+		locs = append(locs, Location{Lat: lat, Lon: lon})
+	}
+	if endpoints {
+		locs = append(locs, Location{Lat: lat2, Lon: ellipsoid.adjustLongitude(lon2, half)})
+	}
+	return locs
+}
+
 /* To returns range, bearing between two specified locations.
 
    dist, theta  = geo.To( lat1, lon1, lat2, lon2 )
 
+With the Karney solver the result is correct for all pairs of points,
+including nearly antipodal ones.
+
 */
 func (ellipsoid Ellipsoid) To(lat1, lon1, lat2, lon2 float64) (distance, bearing float64) {
+	if ellipsoid.GeodesicSolver == Karney {
+		return ellipsoid.toKarney(lat1, lon1, lat2, lon2)
+	}
 
 	if ellipsoid.Units == Degrees {
 		lat1 = deg2rad(lat1)
@@ -245,8 +519,14 @@ specified range and bearing from a given location.
 
     lat2, lon2  = geo.At( lat1, lon1, range, bearing )
 
+With the Karney solver the starting point may be a pole and the range
+may be longer than the circumference of the earth.
+
 */
 func (ellipsoid Ellipsoid) At(lat1, lon1, distance, bearing float64) (lat2, lon2 float64) {
+	if ellipsoid.GeodesicSolver == Karney {
+		return ellipsoid.atKarney(lat1, lon1, distance, bearing)
+	}
 
 	if ellipsoid.Units == Degrees {
 		lat1 = deg2rad(lat1)
@@ -256,16 +536,7 @@ func (ellipsoid Ellipsoid) At(lat1, lon1, distance, bearing float64) (lat2, lon2
 
 	lat2, lon2 = ellipsoid.calculateTargetlocation(lat1, lon1, distance, bearing)
 
-	if ellipsoid.LongitudeSymmetric == LongitudeIsSymmetric {
-		if lon2 > pi {
-			lon2 -= twopi
-		}
-	}
-	if ellipsoid.LongitudeSymmetric == LongitudeNotSymmetric {
-		if lon2 < 0.0 {
-			lon2 += twopi
-		}
-	}
+	lon2 = ellipsoid.adjustLongitude(lon2, pi)
 
 	if ellipsoid.Units == Degrees {
 		lat2 = rad2deg(lat2)
@@ -283,6 +554,7 @@ locations.
 NOTE: The x and y displacements are only approximations and only valid
 between two locations that are fairly near to each other. Beyond 10 kilometers
 or more, the concept of X and Y on a curved surface loses its meaning.
+Use ToENU for exact coordinates in the local tangent plane.
 
 */
 func (ellipsoid Ellipsoid) Displacement(lat1, lon1, lat2, lon2 float64) (x, y float64) {
@@ -319,6 +591,37 @@ func (ellipsoid Ellipsoid) Location(lat1, lon1, x, y float64) (lat, lon float64)
 	return ellipsoid.At(lat1, lon1, range1, bearing1)
 }
 
+/* Scales returns the distance per unit of latitude and per unit of
+longitude at the given latitude, in distance units per degree or per
+radian.
+
+	latScale, lonScale = geo.Scales( lat )
+
+They are handy to build a latitude, longitude window of a given size
+around a location.
+*/
+func (ellipsoid Ellipsoid) Scales(lat float64) (latScale, lonScale float64) {
+	if ellipsoid.Units == Degrees {
+		lat = deg2rad(lat)
+	}
+
+	aa := ellipsoid.Ellipse.Equatorial
+	bb := ellipsoid.Ellipse.polar()
+	d1 := aa * math.Cos(lat)
+	d2 := bb * math.Sin(lat)
+	d3 := d1*d1 + d2*d2
+	d4 := math.Sqrt(d3)
+	n1 := aa * bb
+	latScale = (n1 * n1) / (d3 * d4 * ellipsoid.DistanceFactor)
+	lonScale = (aa * d1) / (d4 * ellipsoid.DistanceFactor)
+
+	if ellipsoid.Units == Degrees {
+		latScale = deg2rad(latScale)
+		lonScale = deg2rad(lonScale)
+	}
+	return latScale, lonScale
+}
+
 func (ellipsoid Ellipsoid) calculateTargetlocation(lat1, lon1, distance, bearing float64) (lat2, lon2 float64) {
 
 	if debug == true {
@@ -520,90 +823,102 @@ func (ellipsoid Ellipsoid) calculateBearing(lat1, lon1, lat2, lon2 float64) (dis
 		fmt.Printf("s=%.8f\n", s)
 	}
 
-	// adjust azimuth to (0,360) or (-180,180) as specified
+	faz = ellipsoid.adjustBearing(faz, pi)
+
+	distance, bearing = s, faz
+	return
+}
+
+// adjustLongitude adjusts a longitude to (-180,180] or [0,360) as
+// specified, where half is 180 for degrees or pi for radians.
+func (ellipsoid Ellipsoid) adjustLongitude(lon, half float64) float64 {
+	if ellipsoid.LongitudeSymmetric == LongitudeIsSymmetric {
+		if lon > half {
+			lon -= 2 * half
+		}
+	}
+	if ellipsoid.LongitudeSymmetric == LongitudeNotSymmetric {
+		if lon < 0.0 {
+			lon += 2 * half
+		}
+	}
+	return lon
+}
+
+// adjustBearing adjusts an azimuth to [0,360) or [-180,180) as specified,
+// where half is 180 for degrees or pi for radians.
+func (ellipsoid Ellipsoid) adjustBearing(faz, half float64) float64 {
 	if ellipsoid.BearingSymmetry == BearingIsSymmetric {
-		if faz < -(pi) {
-			faz += twopi
+		if faz < -half {
+			faz += 2 * half
 		}
-		if faz >= pi {
-			faz -= twopi
+		if faz >= half {
+			faz -= 2 * half
 		}
 	} else {
 		if faz < 0 {
-			faz += twopi
+			faz += 2 * half
 		}
-		if faz >= twopi {
-			faz -= twopi
+		if faz >= 2*half {
+			faz -= 2 * half
 		}
 	}
-
-	distance, bearing = s, faz
-	return
+	return faz
 }
 
-/* ToLLA takes three cartesian coordinates x, y, z and returns
-the latitude, longitude, elevation list.
+/* ToLLA takes three cartesian coordinates x, y, z in meters and returns
+the latitude, longitude, elevation list. The elevation is in the distance
+units; ToECEFUnits is the inverse.
 
-FIXME: This algorithm cannot handle x==0, although this is a valid value.
-WARNING: I put in an if condition to catch this. Is it still necessary?
+The ECEFSolver selects the algorithm. Bowring is an approximation for
+points near the surface of the earth, Vermeille is exact everywhere.
 
 */
 func (ellipsoid Ellipsoid) ToLLA(x, y, z float64) (lat1, lon1, alt1 float64) {
-
-	if x == 0 {
-		fmt.Printf("FATAL: Caught x==0 (div by zero).\n")
-		return
+	if ellipsoid.ECEFSolver == Vermeille {
+		lat1, lon1, alt1 = ellipsoid.Ellipse.vermeille(x, y, z)
+	} else {
+		lat1, lon1, alt1 = ellipsoid.Ellipse.bowring(x, y, z)
 	}
 
-	a := ellipsoid.Ellipse.Equatorial
-	f := 1 / ellipsoid.Ellipse.InvFlattening
+	lon1 = ellipsoid.adjustLongitude(lon1, pi)
+
+	if ellipsoid.Units == Degrees {
+		lat1 = rad2deg(lat1)
+		lon1 = rad2deg(lon1)
+	}
+	return lat1, lon1, alt1 / ellipsoid.DistanceFactor
+}
 
-	b := a * (1.0 - f)
-	e := math.Sqrt((a*a - b*b) / (a * a))
-	e2 := math.Sqrt((a*a - b*b) / (b * b)) // e'
-	esq := e * e                           // e squared
-	e2sq := e2 * e2                        // e' squared
+// bowring returns latitude and longitude in radians and the elevation in
+// meters of the point x, y, z in meters with Bowring's approximation.
+func (e ellipse) bowring(x, y, z float64) (phi, lambda, h float64) {
+	a := e.Equatorial
+	b := e.polar()
+	esq := e.eccentricitySquared()        // e squared
+	e2sq := e.secondEccentricitySquared() // e' squared
 	p := math.Sqrt(x*x + y*y)
 
 	theta := math.Atan2(z*a, p*b)
 	stheta3 := math.Sin(theta) * math.Sin(theta) * math.Sin(theta)
 	ctheta3 := math.Cos(theta) * math.Cos(theta) * math.Cos(theta)
 
-	lon1 = math.Atan2(y, x)
-	phi := math.Atan2(z+e2sq*b*stheta3, p-esq*a*ctheta3)
-	lat1 = phi
-
-	sphisq := math.Sin(phi) * math.Sin(phi)
-	N := a / (math.Sqrt(1 - esq*sphisq))
-	alt1 = p/math.Cos(phi) - N
+	lambda = math.Atan2(y, x)
+	phi = math.Atan2(z+e2sq*b*stheta3, p-esq*a*ctheta3)
 
-	if ellipsoid.LongitudeSymmetric == LongitudeIsSymmetric {
-		if lon1 > pi {
-			lon1 -= twopi
-		}
-	}
-	if ellipsoid.LongitudeSymmetric == LongitudeNotSymmetric {
-		if lon1 < 0.0 {
-			lon1 += twopi
-		}
-	}
-
-	if ellipsoid.Units == Degrees {
-		lat1 = rad2deg(lat1)
-		lon1 = rad2deg(lon1)
-	}
-	return lat1, lon1, alt1
+	// This is p/cos(phi) - N, but it does not fail at the poles.
+	sphi, cphi := math.Sin(phi), math.Cos(phi)
+	h = p*cphi + z*sphi - a*math.Sqrt(1-esq*sphi*sphi)
+	return phi, lambda, h
 }
 
 /* ToECEF takes the latitude, longitude, elevation list and
-   returns three cartesian coordinates x, y, z */
+   returns three cartesian coordinates x, y, z. The elevation and the
+   coordinates are in meters, whatever the distance units. */
 func (ellipsoid Ellipsoid) ToECEF(lat1, lon1, alt1 float64) (x, y, z float64) {
 	a := ellipsoid.Ellipse.Equatorial
-	f := 1 / ellipsoid.Ellipse.InvFlattening
-
-	b := a * (1.0 - f)
-	e := math.Sqrt((a*a - b*b) / (a * a))
-	esq := e * e // e squared
+	b := ellipsoid.Ellipse.polar()
+	esq := ellipsoid.Ellipse.eccentricitySquared() // e squared
 
 	if ellipsoid.Units == Degrees {
 		lat1 = deg2rad(lat1)
@@ -627,6 +942,16 @@ func (ellipsoid Ellipsoid) ToECEF(lat1, lon1, alt1 float64) (x, y, z float64) {
 	return x, y, z
 }
 
+/*
+ToECEFUnits is ToECEF with the elevation in the distance units. The
+coordinates x, y, z are in meters. It is the inverse of ToLLA.
+
+	x, y, z := geo.ToECEFUnits(lat, lon, alt)
+*/
+func (ellipsoid Ellipsoid) ToECEFUnits(lat1, lon1, alt1 float64) (x, y, z float64) {
+	return ellipsoid.ToECEF(lat1, lon1, alt1*ellipsoid.DistanceFactor)
+}
+
 /*
  DEFINED ELLIPSOIDS
 
@@ -659,16 +984,24 @@ semi-major axis in meters and the reciprocal flattening as shown.
 
 plus a few more ...
 
+Names() lists all of them. Names are not case sensitive, and a few
+aliases are accepted, e.g. HAYFORD for INTERNATIONAL.
+
+Ellipsoids that are not listed may be defined at runtime with
+NewCustom, NewFromSemiMinor or NewFromEccentricitySquared, or added
+to the list with Register.
+
  LIMITATIONS
 
-The methods should not be used on points which are too near the poles
-(above or below 89 degrees), and should not be used on points which
-are antipodal, i.e., exactly on opposite sides of the ellipsoid. The
-methods will not return valid results in these cases.
+With the default Vincenty solver, the methods should not be used on
+points which are too near the poles (above or below 89 degrees), and
+should not be used on points which are antipodal, i.e., exactly on
+opposite sides of the ellipsoid. The methods will not return valid
+results in these cases. Select the Karney solver with
+WithGeodesicSolver(Karney) to avoid these limitations.
 
 The Go-version does not support all features of the Perl module. If you
-need advanced features, like defining your own ellipses at runtime,
-calculating x,y dislocations, etc, please refer to the package on CPAN
+need advanced features, please refer to the package on CPAN
 
 Geo::Ellipsoid
 
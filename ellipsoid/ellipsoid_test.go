@@ -2,6 +2,7 @@
 package ellipsoid
 
 import (
+	"errors"
 	"runtime"
 	"strconv"
 
@@ -681,3 +682,287 @@ func TestDisplacement(t *testing.T) {
 		deltaWithin(t, v.loc, y, v.y, epsilon)
 	}
 }
+
+func TestNew(t *testing.T) {
+	e1, err := New("WGS84")
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+	e2 := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingIsSymmetric)
+	if e1 != e2 {
+		t.Errorf("New defaults differ from Init: %+v != %+v", e1, e2)
+	}
+
+	e3, err := New("AIRY", WithUnits(Radians), WithDistanceUnits(Nm),
+		WithLongitudeSymmetry(LongitudeNotSymmetric),
+		WithBearingSymmetry(BearingNotSymmetric))
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+	e4 := Init("AIRY", Radians, Nm, LongitudeNotSymmetric, BearingNotSymmetric)
+	if e3 != e4 {
+		t.Errorf("New options differ from Init: %+v != %+v", e3, e4)
+	}
+
+	_, err = New("NOT-AN-ELLIPSOID")
+	var unknown *UnknownEllipsoidError
+	if !errors.As(err, &unknown) || unknown.Name != "NOT-AN-ELLIPSOID" {
+		t.Errorf("New: expected UnknownEllipsoidError, got %v", err)
+	}
+	e5 := Init("NOT-AN-ELLIPSOID", Radians, Kilometer, LongitudeNotSymmetric, BearingNotSymmetric)
+	want := Ellipsoid{Units: Radians, DistanceUnits: Kilometer,
+		LongitudeSymmetric: LongitudeNotSymmetric, BearingSymmetry: BearingNotSymmetric,
+		DistanceFactor: 1000.0}
+	if e5 != want {
+		t.Errorf("Init: unknown ellipsoid lost the settings: %+v", e5)
+	}
+	e6 := Init("WGS84", Degrees, 5, LongitudeIsSymmetric, BearingIsSymmetric)
+	if e6.DistanceUnits != Meter || e6.DistanceFactor != 1 {
+		t.Errorf("Init: invalid distance unit did not fall back to Meter: %+v", e6)
+	}
+	if d, _ := e6.To(0, 0, 0, 1); math.IsInf(d, 0) || math.IsNaN(d) {
+		t.Errorf("Init: distance %v with an invalid distance unit", d)
+	}
+
+	var invalid *InvalidUnitError
+	_, err = New("WGS84", WithDistanceUnits(5))
+	if !errors.As(err, &invalid) || invalid.Kind != "distance" {
+		t.Errorf("New: expected distance InvalidUnitError, got %v", err)
+	}
+	_, err = New("WGS84", WithUnits(Meter))
+	if !errors.As(err, &invalid) || invalid.Kind != "angle" {
+		t.Errorf("New: expected angle InvalidUnitError, got %v", err)
+	}
+}
+
+func TestNewCustom(t *testing.T) {
+	wgs84 := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingIsSymmetric)
+	custom, err := NewCustom(6378137.0, 298.257223563)
+	if err != nil {
+		t.Fatalf("NewCustom: unexpected error %v", err)
+	}
+	d1, b1 := wgs84.To(37.619002, -122.374843, 33.942536, -118.408074)
+	d2, b2 := custom.To(37.619002, -122.374843, 33.942536, -118.408074)
+	deltaWithin(t, loc(), d2, d1, 1e-6)
+	deltaWithin(t, loc(), b2, b1, 1e-9)
+
+	b := 6378137.0 * (1 - 1/298.257223563)
+	minor, err := NewFromSemiMinor(6378137.0, b)
+	if err != nil {
+		t.Fatalf("NewFromSemiMinor: unexpected error %v", err)
+	}
+	deltaWithin(t, loc(), minor.Ellipse.InvFlattening, 298.257223563, 1e-6)
+
+	f := 1 / 298.257223563
+	ecc, err := NewFromEccentricitySquared(6378137.0, f*(2-f))
+	if err != nil {
+		t.Fatalf("NewFromEccentricitySquared: unexpected error %v", err)
+	}
+	deltaWithin(t, loc(), ecc.Ellipse.InvFlattening, 298.257223563, 1e-6)
+
+	// On a sphere the distance is the great circle distance.
+	r := 6371000.0
+	sphere, err := NewCustom(r, 0, WithDistanceUnits(Kilometer))
+	if err != nil {
+		t.Fatalf("NewCustom: unexpected error %v", err)
+	}
+	dist, bear := sphere.To(0, 0, 0, 90)
+	deltaWithin(t, loc(), dist, r*pi/2/1000, 1e-6)
+	deltaWithin(t, loc(), bear, 90, 1e-9)
+	lat, lon := sphere.At(0, 0, r*pi/2/1000, 0)
+	deltaWithin(t, loc(), lat, 90, 1e-6)
+	deltaWithin(t, loc(), lon, 0, 1e-6)
+	x, y, z := sphere.ToECEF(45, 45, 0)
+	lat, lon, h := sphere.ToLLA(x, y, z)
+	deltaWithin(t, loc(), lat, 45, 1e-9)
+	deltaWithin(t, loc(), lon, 45, 1e-9)
+	deltaWithin(t, loc(), h, 0, 1e-6)
+
+	var invalid *InvalidEllipseError
+	if _, err := NewCustom(-1, 298); !errors.As(err, &invalid) {
+		t.Errorf("NewCustom: expected InvalidEllipseError, got %v", err)
+	}
+	if _, err := NewCustom(6378137, 0.5); !errors.As(err, &invalid) {
+		t.Errorf("NewCustom: expected InvalidEllipseError, got %v", err)
+	}
+	if _, err := NewFromSemiMinor(6378137, 6400000); !errors.As(err, &invalid) {
+		t.Errorf("NewFromSemiMinor: expected InvalidEllipseError, got %v", err)
+	}
+	if _, err := NewFromEccentricitySquared(6378137, 1); !errors.As(err, &invalid) {
+		t.Errorf("NewFromEccentricitySquared: expected InvalidEllipseError, got %v", err)
+	}
+}
+
+func TestIntermediateLocations(t *testing.T) {
+	e := Init("WGS84", Degrees, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	d, b, arr := e.Intermediate(40.6, -73.8, 51.6, -0.5, 4)
+	d2, b2, locs := e.IntermediateLocations(40.6, -73.8, 51.6, -0.5, 4, IncludeEndpoints)
+	deltaWithin(t, loc(), d2, d, 1e-12)
+	deltaWithin(t, loc(), b2, b, 1e-12)
+	if len(locs) != 5 {
+		t.Fatalf("IntermediateLocations: got %d locations, want 5", len(locs))
+	}
+	for i, l := range locs {
+		deltaWithin(t, loc(), l.Lat, arr[2*i], 1e-9)
+		deltaWithin(t, loc(), l.Lon, arr[2*i+1], 1e-9)
+	}
+
+	_, _, inner := e.IntermediateLocations(40.6, -73.8, 51.6, -0.5, 4, ExcludeEndpoints)
+	if len(inner) != 3 || inner[0] != locs[1] || inner[2] != locs[3] {
+		t.Errorf("IntermediateLocations: got %v, want %v", inner, locs[1:4])
+	}
+
+	if _, _, locs := e.IntermediateLocations(40.6, -73.8, 51.6, -0.5, 0, IncludeEndpoints); locs != nil {
+		t.Errorf("IntermediateLocations: got %v for 0 steps", locs)
+	}
+
+	// The Karney solver samples a GeodesicLine.
+	k, _ := New("WGS84", WithDistanceUnits(Kilometer), WithGeodesicSolver(Karney))
+	_, _, klocs := k.IntermediateLocations(40.6, -73.8, 51.6, -0.5, 4, IncludeEndpoints)
+	for i, l := range klocs {
+		deltaWithin(t, loc(), l.Lat, locs[i].Lat, 1e-9)
+		deltaWithin(t, loc(), l.Lon, locs[i].Lon, 1e-9)
+	}
+	// Nearly antipodal points, where Vincenty does not converge.
+	d, _, klocs = k.IntermediateLocations(0.5, 0, -0.5, 179.7, 4, ExcludeEndpoints)
+	for i, l := range klocs {
+		s, _ := k.To(0.5, 0, l.Lat, l.Lon)
+		deltaWithin(t, loc(), s, d*float64(i+1)/4, 1e-9)
+		s, _ = k.To(l.Lat, l.Lon, -0.5, 179.7)
+		deltaWithin(t, loc(), s, d*float64(3-i)/4, 1e-9)
+	}
+}
+
+func TestDensify(t *testing.T) {
+	e := Init("WGS84", Radians, Kilometer, LongitudeNotSymmetric, BearingIsSymmetric)
+	lat1, lon1 := 40.6*pi/180, -73.8*pi/180
+	lat2, lon2 := 51.6*pi/180, -0.5*pi/180
+
+	d, _, locs := e.Densify(lat1, lon1, lat2, lon2, 5, IncludeEndpoints)
+	want := int(math.Ceil(d/5)) + 1
+	if len(locs) != want {
+		t.Fatalf("Densify: got %d locations, want %d", len(locs), want)
+	}
+	if locs[0].Lat != lat1 || locs[len(locs)-1].Lat != lat2 {
+		t.Errorf("Densify: endpoints are %v and %v", locs[0], locs[len(locs)-1])
+	}
+	deltaWithin(t, loc(), locs[len(locs)-1].Lon, lon2+2*pi, 1e-15)
+	for i := 1; i < len(locs); i++ {
+		s, _ := e.To(locs[i-1].Lat, locs[i-1].Lon, locs[i].Lat, locs[i].Lon)
+		if s > 5 {
+			t.Errorf("Densify: spacing %v exceeds 5 km", s)
+		}
+		deltaWithin(t, loc(), s, d/float64(len(locs)-1), 1e-6)
+	}
+
+	_, _, inner := e.Densify(lat1, lon1, lat2, lon2, 5, ExcludeEndpoints)
+	if len(inner) != len(locs)-2 {
+		t.Errorf("Densify: got %d inner locations, want %d", len(inner), len(locs)-2)
+	}
+
+	_, _, locs = e.Densify(lat1, lon1, lat2, lon2, 1e6, IncludeEndpoints)
+	if len(locs) != 2 {
+		t.Errorf("Densify: got %d locations for a long spacing, want 2", len(locs))
+	}
+	if _, _, locs = e.Densify(lat1, lon1, lat2, lon2, 0, IncludeEndpoints); locs != nil {
+		t.Errorf("Densify: got %v for zero spacing", locs)
+	}
+	if _, _, locs = e.Densify(0, 0, 10, 10, 1e-12, IncludeEndpoints); locs != nil {
+		t.Errorf("Densify: got %d locations for a tiny spacing", len(locs))
+	}
+}
+
+func TestToLLAVermeille(t *testing.T) {
+	e1, err := New("WGS84", WithECEFSolver(Vermeille))
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+	b := e1.Ellipse.Equatorial * (1 - 1/e1.Ellipse.InvFlattening)
+
+	allTests := []testobjecttoECEF{
+		{loc(), 30.2746722, -97.7403306, 0.0, -742507.1, -5462738.5, 3196706.5},
+		{loc(), 38.649882, -77.134602, -3261.28, 1110000., -4860000., 3960000.},
+		{loc(), 37.89038, 126.73316, 23.0, -3014326.6, 4039148.7, 3895863.},
+		// x == 0, the poles and the centre of the earth
+		{loc(), 0, 90, 0, 0, 6378137, 0},
+		{loc(), 0, -90, 1000, 0, -6379137, 0},
+		{loc(), 90, 0, 0, 0, 0, b},
+		{loc(), -90, 0, 100, 0, 0, -b - 100},
+		{loc(), 90, 0, -b, 0, 0, 0}}
+	for _, v := range allTests {
+		lat, lon, h := e1.ToLLA(v.x, v.y, v.z)
+		deltaWithin(t, v.loc, lat, v.lat, 1e-6)
+		deltaWithin(t, v.loc, lon, v.lon, 1e-6)
+		deltaWithin(t, v.loc, h, v.h, 1.0)
+	}
+
+	// Round trips from deep inside the earth to beyond the moon.
+	r := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingIsSymmetric)
+	r.ECEFSolver = Vermeille
+	for _, h := range []float64{-6000, -100, 0, 1, 400, 36000, 400000} {
+		for lat := -90.0; lat <= 90; lat += 7.5 {
+			x, y, z := r.ToECEFUnits(lat*degree, 30*degree, h)
+			la, lo, hh := r.ToLLA(x, y, z)
+			deltaWithin(t, loc(), la, lat*degree, 1e-14)
+			if math.Abs(lat) != 90 {
+				deltaWithin(t, loc(), lo, 30*degree, 1e-14)
+			}
+			deltaWithin(t, loc(), hh, h, 1e-9)
+		}
+	}
+
+	if _, err := New("WGS84", WithECEFSolver(2)); err == nil {
+		t.Errorf("New: accepted ECEF solver 2")
+	}
+}
+
+func TestECEFDistanceUnits(t *testing.T) {
+	m := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingIsSymmetric)
+	k := Init("WGS84", Degrees, Kilometer, LongitudeIsSymmetric, BearingIsSymmetric)
+
+	// ToECEF works in meters whatever the distance units.
+	x, y, z := m.ToECEF(38.649882, -77.134602, -3261.28)
+	if xk, yk, zk := k.ToECEF(38.649882, -77.134602, -3261.28); xk != x || yk != y || zk != z {
+		t.Errorf("ToECEF: %v, %v, %v in kilometers differ from %v, %v, %v", xk, yk, zk, x, y, z)
+	}
+
+	// The elevation of ToLLA and ToECEFUnits is in the distance units.
+	xk, yk, zk := k.ToECEFUnits(38.649882, -77.134602, -3.26128)
+	deltaWithin(t, loc(), xk, x, 1e-6)
+	deltaWithin(t, loc(), yk, y, 1e-6)
+	deltaWithin(t, loc(), zk, z, 1e-6)
+	_, _, h := k.ToLLA(x, y, z)
+	deltaWithin(t, loc(), h, -3.26128, 1e-6)
+
+	// Bowring no longer fails on the 90 degree meridian and at the poles.
+	lat, lon, h := m.ToLLA(0, 6378137, 0)
+	deltaWithin(t, loc(), lat, 0, 1e-9)
+	deltaWithin(t, loc(), lon, 90, 1e-9)
+	deltaWithin(t, loc(), h, 0, 1e-6)
+	lat, _, h = m.ToLLA(0, 0, -6356752.314245)
+	deltaWithin(t, loc(), lat, -90, 1e-9)
+	deltaWithin(t, loc(), h, 0, 1e-6)
+}
+
+func TestScales(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	latScale, lonScale := e.Scales(0)
+	deltaWithin(t, loc(), latScale, 110574.275822, 1e-6)
+	deltaWithin(t, loc(), lonScale, 111319.490793274, 1e-6)
+
+	for _, lat := range []float64{-75, -30, 0, 45, 60, 89} {
+		latScale, lonScale = e.Scales(lat)
+		deltaWithin(t, loc(), latScale, e.MeridionalRadius(lat)*degree, 1e-6)
+		deltaWithin(t, loc(), lonScale, e.PrimeVerticalRadius(lat)*math.Cos(lat*degree)*degree, 1e-6)
+	}
+
+	latScale, lonScale = e.Scales(90)
+	deltaWithin(t, loc(), lonScale, 0, 1e-9)
+
+	r := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	latScale, lonScale = r.Scales(0)
+	deltaWithin(t, loc(), latScale, 6335.439327, 1e-6)
+	deltaWithin(t, loc(), lonScale, 6378.137, 1e-9)
+}
@@ -0,0 +1,156 @@
+package ellipsoid
+
+import "math"
+
+// rotation returns the matrix whose rows are the unit vectors east,
+// north and up in ECEF at the latitude phi and longitude lam in radians.
+// It rotates ECEF vectors into the local ENU frame.
+func rotation(phi, lam float64) [3][3]float64 {
+	sphi, cphi := math.Sin(phi), math.Cos(phi)
+	slam, clam := math.Sin(lam), math.Cos(lam)
+	return [3][3]float64{
+		{-slam, clam, 0},
+		{-sphi * clam, -sphi * slam, cphi},
+		{cphi * clam, cphi * slam, sphi},
+	}
+}
+
+// rotate returns r v.
+func rotate(r *[3][3]float64, x, y, z float64) (float64, float64, float64) {
+	return r[0][0]*x + r[0][1]*y + r[0][2]*z,
+		r[1][0]*x + r[1][1]*y + r[1][2]*z,
+		r[2][0]*x + r[2][1]*y + r[2][2]*z
+}
+
+// unrotate returns the transpose of r times v, the inverse of rotate.
+func unrotate(r *[3][3]float64, x, y, z float64) (float64, float64, float64) {
+	return r[0][0]*x + r[1][0]*y + r[2][0]*z,
+		r[0][1]*x + r[1][1]*y + r[2][1]*z,
+		r[0][2]*x + r[1][2]*y + r[2][2]*z
+}
+
+// LocalCartesian is a local tangent plane with a fixed origin. It caches
+// the ECEF coordinates of the origin and the rotation matrix, so that
+// converting many points around one site is cheap. The coordinates are
+// east, north and up in the distance units.
+type LocalCartesian struct {
+	ellipsoid  Ellipsoid
+	lat0, lon0 float64
+	h0         float64
+	x0, y0, z0 float64
+	r          [3][3]float64
+}
+
+/*
+NewLocalCartesian returns the local tangent plane with the origin lat0,
+lon0, h0.
+
+	lc := geo.NewLocalCartesian(lat0, lon0, h0)
+	e, n, u := lc.Forward(lat, lon, h)
+	lat, lon, h = lc.Reverse(e, n, u)
+*/
+func (ellipsoid Ellipsoid) NewLocalCartesian(lat0, lon0, h0 float64) LocalCartesian {
+	lc := LocalCartesian{ellipsoid: ellipsoid, lat0: lat0, lon0: lon0, h0: h0}
+	lc.x0, lc.y0, lc.z0 = ellipsoid.ToECEFUnits(lat0, lon0, h0)
+	if ellipsoid.Units == Degrees {
+		lat0 = deg2rad(lat0)
+		lon0 = deg2rad(lon0)
+	}
+	lc.r = rotation(lat0, lon0)
+	return lc
+}
+
+// Origin returns the origin of the local tangent plane.
+func (lc LocalCartesian) Origin() (lat0, lon0, h0 float64) {
+	return lc.lat0, lc.lon0, lc.h0
+}
+
+/*
+Rotation returns the matrix that rotates a vector from ECEF into the
+local frame. Its rows are the unit vectors east, north and up in ECEF;
+its transpose rotates back. Use it to rotate velocities and
+covariances.
+
+	r := lc.Rotation()
+	ve := r[0][0]*vx + r[0][1]*vy + r[0][2]*vz
+*/
+func (lc LocalCartesian) Rotation() [3][3]float64 {
+	return lc.r
+}
+
+/*
+Forward returns the east, north and up coordinates of the point lat, lon, h.
+
+	e, n, u := lc.Forward(lat, lon, h)
+*/
+func (lc LocalCartesian) Forward(lat, lon, h float64) (e, n, u float64) {
+	return lc.fromECEF(lc.ellipsoid.ToECEFUnits(lat, lon, h))
+}
+
+// fromECEF returns the local coordinates in the distance units of the
+// ECEF point x, y, z in meters.
+func (lc LocalCartesian) fromECEF(x, y, z float64) (e, n, u float64) {
+	f := lc.ellipsoid.DistanceFactor
+	e, n, u = rotate(&lc.r, x-lc.x0, y-lc.y0, z-lc.z0)
+	return e / f, n / f, u / f
+}
+
+// toECEF returns the ECEF coordinates in meters of the local point e, n,
+// u in the distance units.
+func (lc LocalCartesian) toECEF(e, n, u float64) (x, y, z float64) {
+	f := lc.ellipsoid.DistanceFactor
+	dx, dy, dz := unrotate(&lc.r, e*f, n*f, u*f)
+	return lc.x0 + dx, lc.y0 + dy, lc.z0 + dz
+}
+
+/*
+Reverse returns the point at the east, north and up coordinates e, n, u.
+
+	lat, lon, h := lc.Reverse(e, n, u)
+*/
+func (lc LocalCartesian) Reverse(e, n, u float64) (lat, lon, h float64) {
+	return lc.ellipsoid.ToLLA(lc.toECEF(e, n, u))
+}
+
+/*
+ToENU returns the east, north and up coordinates of the point lat, lon, h
+in the local tangent plane with the origin lat0, lon0, h0. The heights
+and the coordinates are in the distance units. Unlike Displacement this
+is exact at any distance. Use a LocalCartesian for many points around one
+origin.
+
+	e, n, u := geo.ToENU(lat0, lon0, h0, lat, lon, h)
+*/
+func (ellipsoid Ellipsoid) ToENU(lat0, lon0, h0, lat, lon, h float64) (e, n, u float64) {
+	return ellipsoid.NewLocalCartesian(lat0, lon0, h0).Forward(lat, lon, h)
+}
+
+/*
+FromENU is the inverse of ToENU. It returns the point at the east, north
+and up coordinates e, n, u in the local tangent plane with the origin
+lat0, lon0, h0.
+
+	lat, lon, h := geo.FromENU(lat0, lon0, h0, e, n, u)
+*/
+func (ellipsoid Ellipsoid) FromENU(lat0, lon0, h0, e, n, u float64) (lat, lon, h float64) {
+	return ellipsoid.NewLocalCartesian(lat0, lon0, h0).Reverse(e, n, u)
+}
+
+/*
+ToNED is ToENU with the axes north, east and down.
+
+	n, e, d := geo.ToNED(lat0, lon0, h0, lat, lon, h)
+*/
+func (ellipsoid Ellipsoid) ToNED(lat0, lon0, h0, lat, lon, h float64) (n, e, d float64) {
+	e, n, u := ellipsoid.ToENU(lat0, lon0, h0, lat, lon, h)
+	return n, e, -u
+}
+
+/*
+FromNED is the inverse of ToNED.
+
+	lat, lon, h := geo.FromNED(lat0, lon0, h0, n, e, d)
+*/
+func (ellipsoid Ellipsoid) FromNED(lat0, lon0, h0, n, e, d float64) (lat, lon, h float64) {
+	return ellipsoid.FromENU(lat0, lon0, h0, e, n, -d)
+}
@@ -0,0 +1,120 @@
+package ellipsoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToENU(t *testing.T) {
+	e1 := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	// Straight up.
+	e, n, u := e1.ToENU(45, 7, 100, 45, 7, 1100)
+	deltaWithin(t, loc(), e, 0, 1e-6)
+	deltaWithin(t, loc(), n, 0, 1e-6)
+	deltaWithin(t, loc(), u, 1000, 1e-6)
+
+	// Along the parallel the point lies on a circle of radius N cos(phi)
+	// around the axis.
+	a := e1.Ellipse.Equatorial
+	esq := e1.Ellipse.eccentricitySquared()
+	phi, dlam := 45*degree, 30*degree
+	p := a / math.Sqrt(1-esq*sq(math.Sin(phi))) * math.Cos(phi)
+	e, n, u = e1.ToENU(45, 7, 0, 45, 37, 0)
+	deltaWithin(t, loc(), e, p*math.Sin(dlam), 1e-6)
+	deltaWithin(t, loc(), n, math.Sin(phi)*p*(1-math.Cos(dlam)), 1e-6)
+	deltaWithin(t, loc(), u, -math.Cos(phi)*p*(1-math.Cos(dlam)), 1e-6)
+
+	// The far side of the earth is far below.
+	_, _, u = e1.ToENU(0, 0, 0, 0, 180, 0)
+	deltaWithin(t, loc(), u, -2*a, 1e-6)
+
+	nn, ee, d := e1.ToNED(45, 7, 0, 45, 37, 0)
+	e, n, u = e1.ToENU(45, 7, 0, 45, 37, 0)
+	if nn != n || ee != e || d != -u {
+		t.Errorf("ToNED: got %v %v %v for ENU %v %v %v", nn, ee, d, e, n, u)
+	}
+}
+
+func TestFromENU(t *testing.T) {
+	e1 := Init("WGS84", Radians, Meter, LongitudeNotSymmetric, BearingNotSymmetric)
+
+	lat0, lon0, h0 := 52.5*degree, 13.4*degree, 34.0
+	for _, p := range [][3]float64{
+		{52.6 * degree, 13.2 * degree, 100},
+		{-33.9 * degree, 151.2 * degree, 10},
+		{52.5 * degree, 13.4 * degree, 10e3},
+	} {
+		e, n, u := e1.ToENU(lat0, lon0, h0, p[0], p[1], p[2])
+		lat, lon, h := e1.FromENU(lat0, lon0, h0, e, n, u)
+		deltaWithin(t, loc(), lat, p[0], 1e-9)
+		deltaWithin(t, loc(), lon, p[1], 1e-9)
+		deltaWithin(t, loc(), h, p[2], 1e-3)
+
+		nn, ee, d := e1.ToNED(lat0, lon0, h0, p[0], p[1], p[2])
+		lat, lon, h = e1.FromNED(lat0, lon0, h0, nn, ee, d)
+		deltaWithin(t, loc(), lat, p[0], 1e-9)
+		deltaWithin(t, loc(), lon, p[1], 1e-9)
+		deltaWithin(t, loc(), h, p[2], 1e-3)
+	}
+}
+
+func TestLocalCartesian(t *testing.T) {
+	e1 := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	lc := e1.NewLocalCartesian(52.5, 13.4, 34)
+	if lat0, lon0, h0 := lc.Origin(); lat0 != 52.5 || lon0 != 13.4 || h0 != 34 {
+		t.Errorf("Origin: got %v %v %v", lat0, lon0, h0)
+	}
+	e, n, u := lc.Forward(52.6, 13.2, 100)
+	we, wn, wu := e1.ToENU(52.5, 13.4, 34, 52.6, 13.2, 100)
+	if e != we || n != wn || u != wu {
+		t.Errorf("Forward: got %v %v %v, want %v %v %v", e, n, u, we, wn, wu)
+	}
+	lat, lon, h := lc.Reverse(e, n, u)
+	deltaWithin(t, loc(), lat, 52.6, 1e-9)
+	deltaWithin(t, loc(), lon, 13.2, 1e-9)
+	deltaWithin(t, loc(), h, 100, 1e-3)
+
+	// The rotation is orthonormal and maps the ECEF difference to ENU.
+	r := lc.Rotation()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			dot := r[i][0]*r[j][0] + r[i][1]*r[j][1] + r[i][2]*r[j][2]
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			deltaWithin(t, loc(), dot, want, 1e-15)
+		}
+	}
+	x0, y0, z0 := e1.ToECEF(52.5, 13.4, 34)
+	x, y, z := e1.ToECEF(52.6, 13.2, 100)
+	dx, dy, dz := x-x0, y-y0, z-z0
+	deltaWithin(t, loc(), r[0][0]*dx+r[0][1]*dy+r[0][2]*dz, e, 1e-9)
+	deltaWithin(t, loc(), r[1][0]*dx+r[1][1]*dy+r[1][2]*dz, n, 1e-9)
+	deltaWithin(t, loc(), r[2][0]*dx+r[2][1]*dy+r[2][2]*dz, u, 1e-9)
+
+	// Up is the normal of the ellipsoid at the origin.
+	deltaWithin(t, loc(), math.Asin(r[2][2]), 52.5*degree, 1e-15)
+	deltaWithin(t, loc(), math.Atan2(r[2][1], r[2][0]), 13.4*degree, 1e-15)
+}
+
+func TestLocalCartesianUnits(t *testing.T) {
+	m := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingIsSymmetric)
+	k := Init("WGS84", Degrees, Kilometer, LongitudeIsSymmetric, BearingIsSymmetric)
+
+	e, n, u := m.ToENU(52.5, 13.4, 34, 52.6, 13.2, 100)
+	ek, nk, uk := k.ToENU(52.5, 13.4, 0.034, 52.6, 13.2, 0.1)
+	deltaWithin(t, loc(), ek, e/1000, 1e-9)
+	deltaWithin(t, loc(), nk, n/1000, 1e-9)
+	deltaWithin(t, loc(), uk, u/1000, 1e-9)
+	lat, lon, h := k.FromENU(52.5, 13.4, 0.034, ek, nk, uk)
+	deltaWithin(t, loc(), lat, 52.6, 1e-12)
+	deltaWithin(t, loc(), lon, 13.2, 1e-12)
+	deltaWithin(t, loc(), h, 0.1, 1e-9)
+
+	_, _, slant := m.LookAngles(52.5, 13.4, 34, 52.6, 13.2, 100)
+	_, _, slantk := k.LookAngles(52.5, 13.4, 0.034, 52.6, 13.2, 0.1)
+	deltaWithin(t, loc(), slantk, slant/1000, 1e-9)
+}
@@ -0,0 +1,98 @@
+package ellipsoid
+
+import "fmt"
+
+// UnknownEllipsoidError is returned by New when the requested
+// ellipsoid is not defined.
+type UnknownEllipsoidError struct {
+	Name string
+}
+
+func (e *UnknownEllipsoidError) Error() string {
+	return fmt.Sprintf("ellipsoid: unknown ellipsoid %q", e.Name)
+}
+
+// InvalidUnitError is returned by New when an angle or distance unit
+// is not one of the defined constants.
+type InvalidUnitError struct {
+	Kind string // "angle" or "distance"
+	Unit int
+}
+
+func (e *InvalidUnitError) Error() string {
+	return fmt.Sprintf("ellipsoid: invalid %s unit %d", e.Kind, e.Unit)
+}
+
+// InvalidEllipseError is returned when the parameters of a custom
+// ellipsoid do not describe an oblate ellipsoid or a sphere.
+type InvalidEllipseError struct {
+	Param string
+	Value float64
+}
+
+func (e *InvalidEllipseError) Error() string {
+	return fmt.Sprintf("ellipsoid: invalid %s %v", e.Param, e.Value)
+}
+
+// DuplicateEllipsoidError is returned by Register and RegisterAlias
+// when the name is already in use.
+type DuplicateEllipsoidError struct {
+	Name string
+}
+
+func (e *DuplicateEllipsoidError) Error() string {
+	return fmt.Sprintf("ellipsoid: ellipsoid %q is already registered", e.Name)
+}
+
+// InvalidNameError is returned by Register and RegisterAlias when the
+// name is empty.
+type InvalidNameError struct {
+	Name string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("ellipsoid: invalid ellipsoid name %q", e.Name)
+}
+
+// InvalidSolverError is returned by New when a solver is not one of
+// the defined constants.
+type InvalidSolverError struct {
+	Kind   string // "geodesic" or "ECEF"
+	Solver int
+}
+
+func (e *InvalidSolverError) Error() string {
+	return fmt.Sprintf("ellipsoid: invalid %s solver %d", e.Kind, e.Solver)
+}
+
+// OutOfRangeError is returned when a coordinate or a grid parameter is
+// outside the domain of a conversion.
+type OutOfRangeError struct {
+	Param string
+	Value float64
+}
+
+func (e *OutOfRangeError) Error() string {
+	return fmt.Sprintf("ellipsoid: %s %v out of range", e.Param, e.Value)
+}
+
+// InvalidMGRSError is returned by FromMGRS when a grid reference cannot
+// be parsed.
+type InvalidMGRSError struct {
+	Ref    string
+	Reason string
+}
+
+func (e *InvalidMGRSError) Error() string {
+	return fmt.Sprintf("ellipsoid: invalid MGRS reference %q: %s", e.Ref, e.Reason)
+}
+
+// InvalidQuadkeyError is returned by TileFromQuadkey when a quadkey has
+// a digit other than 0 to 3.
+type InvalidQuadkeyError struct {
+	Quadkey string
+}
+
+func (e *InvalidQuadkeyError) Error() string {
+	return fmt.Sprintf("ellipsoid: invalid quadkey %q", e.Quadkey)
+}
@@ -0,0 +1,1358 @@
+package ellipsoid
+
+// Karney's algorithms for geodesics on an ellipsoid of revolution, see
+//
+//	C. F. F. Karney, Algorithms for geodesics,
+//	J. Geodesy 87, 43-55 (2013), https://doi.org/10.1007/s00190-012-0578-z
+//
+// This is a port of the C implementation in GeographicLib by
+// Charles Karney (MIT/X11 License). The series are carried to sixth
+// order in the flattening, which gives full double precision for the
+// ellipsoids of the earth. The geodesic code works in degrees and
+// meters throughout; the methods of Ellipsoid take care of the units.
+
+import (
+	"math"
+	"sync"
+)
+
+const (
+	geodOrder = 6
+	nA1       = geodOrder
+	nC1       = geodOrder
+	nC1p      = geodOrder
+	nA2       = geodOrder
+	nC2       = geodOrder
+	nA3       = geodOrder
+	nA3x      = nA3
+	nC3       = geodOrder
+	nC3x      = (nC3 * (nC3 - 1)) / 2
+	nC4       = geodOrder
+	nC4x      = (nC4 * (nC4 + 1)) / 2
+	nC        = geodOrder + 1
+	maxit1    = 20
+	maxit2    = maxit1 + 53 + 10 // 53 is the number of digits of a float64
+	degree    = pi / 180
+)
+
+// Bits of the output masks of genInverse and genPosition. The low bits say which
+// series coefficients are needed to compute an output.
+const (
+	capC1  = 1 << 0
+	capC1p = 1 << 1
+	capC2  = 1 << 2
+	capC3  = 1 << 3
+	capC4  = 1 << 4
+	capAll = 0x1f
+	outAll = 0x7f80
+
+	outLatitude      = 1 << 7
+	outLongitude     = 1<<8 | capC3
+	outAzimuth       = 1 << 9
+	outDistance      = 1<<10 | capC1
+	outDistanceIn    = 1<<11 | capC1 | capC1p
+	outReducedLength = 1<<12 | capC1 | capC2
+	outGeodesicScale = 1<<13 | capC1 | capC2
+	outArea          = 1<<14 | capC4
+	outLongUnroll    = 1 << 15
+)
+
+var (
+	dblEpsilon = math.Nextafter(1, 2) - 1
+	tiny       = math.Sqrt(math.Float64frombits(0x0010000000000000)) // sqrt of the smallest normal
+	tol0       = dblEpsilon
+	tol1       = 200 * tol0
+	tol2       = math.Sqrt(tol0)
+	tolb       = tol0
+	xthresh    = 1000 * tol2
+)
+
+// geodesic holds the constants of the series for one ellipsoid.
+type geodesic struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+
+	a3x [nA3x]float64
+	c3x [nC3x]float64
+	c4x [nC4x]float64
+}
+
+// maxCachedEllipses bounds the number of ellipses whose series
+// constants are cached.
+const maxCachedEllipses = 64
+
+// geodesics caches the geodesic of each ellipse in use, as setting up
+// the series is far more costly than solving a single problem. A
+// geodesic is not modified after newGeodesic returns it.
+var geodesics = struct {
+	sync.RWMutex
+	m map[ellipse]*geodesic
+}{m: map[ellipse]*geodesic{}}
+
+// cachedGeodesic returns the geodesic of e, computing it on first use.
+func cachedGeodesic(e ellipse) *geodesic {
+	geodesics.RLock()
+	g, ok := geodesics.m[e]
+	geodesics.RUnlock()
+	if ok {
+		return g
+	}
+	g = newGeodesic(e)
+	geodesics.Lock()
+	defer geodesics.Unlock()
+	if len(geodesics.m) < maxCachedEllipses {
+		geodesics.m[e] = g
+	}
+	return g
+}
+
+func newGeodesic(e ellipse) *geodesic {
+	g := &geodesic{a: e.Equatorial, f: 1 / e.InvFlattening}
+	g.f1 = 1 - g.f
+	g.e2 = g.f * (2 - g.f)
+	g.ep2 = g.e2 / sq(g.f1)
+	g.n = g.f / (2 - g.f)
+	g.b = g.a * g.f1
+	var c float64
+	switch {
+	case g.e2 == 0:
+		c = 1
+	case g.e2 > 0:
+		c = math.Atanh(math.Sqrt(g.e2)) / math.Sqrt(g.e2)
+	default:
+		c = math.Atan(math.Sqrt(-g.e2)) / math.Sqrt(-g.e2)
+	}
+	g.c2 = (sq(g.a) + sq(g.b)*c) / 2
+	// The sig12 threshold for "really short". Using the auxiliary sphere
+	// solution with dnm computed at (bet1 + bet2) / 2, the relative error
+	// in the azimuth consistency check is sig12^2 * abs(f) * min(1, 1-f/2)
+	// / 2.
+	g.etol2 = 0.1 * tol2 / math.Sqrt(math.Max(0.001, math.Abs(g.f))*math.Min(1, 1-g.f/2)/2)
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+	return g
+}
+
+func sq(x float64) float64 {
+	return x * x
+}
+
+// sumx returns the sum s of u and v and its round-off error t.
+func sumx(u, v float64) (s, t float64) {
+	s = u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	if s != 0 {
+		t = 0 - (up + vpp)
+	} else {
+		t = s
+	}
+	return s, t
+}
+
+// polyval evaluates the polynomial of degree n with the coefficients p,
+// highest order first, at x.
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+// angRound rounds tiny angles so that the equator and the meridians are
+// treated consistently.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	if x == 0 {
+		return 0
+	}
+	y := math.Abs(x)
+	// z - (z - y) is not y: this removes the low bits of small angles.
+	if y < z {
+		y = z - (z - y)
+	}
+	if x < 0 {
+		return -y
+	}
+	return y
+}
+
+// latFix returns NaN for latitudes outside [-90, 90].
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// angNormalize reduces an angle in degrees to (-180, 180].
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if math.Abs(y) == 180 {
+		return math.Copysign(180, x)
+	}
+	return y
+}
+
+// angDiff returns y - x reduced to [-180, 180] and the error e of the
+// reduction, computed accurately.
+func angDiff(x, y float64) (d, e float64) {
+	d, t := sumx(math.Remainder(-x, 360), math.Remainder(y, 360))
+	d, t = sumx(math.Remainder(d, 360), t)
+	if d == 0 || math.Abs(d) == 180 {
+		if t == 0 {
+			d = math.Copysign(d, y-x)
+		} else {
+			d = math.Copysign(d, -t)
+		}
+	}
+	return d, t
+}
+
+// sincosdx returns the sine and cosine of an angle in degrees, exact
+// for multiples of 90 degrees.
+func sincosdx(x float64) (sinx, cosx float64) {
+	r := math.Mod(x, 360)
+	q := int(math.Floor(r/90 + 0.5))
+	r -= 90 * float64(q)
+	r *= degree
+	s, c := math.Sin(r), math.Cos(r)
+	switch uint(q) & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+	cosx += 0
+	if sinx == 0 {
+		sinx = math.Copysign(sinx, x)
+	}
+	return sinx, cosx
+}
+
+// atan2dx returns atan2(y, x) in degrees, exact for multiples of 90
+// degrees.
+func atan2dx(y, x float64) float64 {
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+	if x < 0 {
+		x = -x
+		q++
+	}
+	// Here x >= 0 and x >= abs(y), so the angle is in [-45, 45].
+	ang := math.Atan2(y, x) / degree
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
+
+func norm2(sinx, cosx float64) (float64, float64) {
+	r := math.Hypot(sinx, cosx)
+	return sinx / r, cosx / r
+}
+
+// sinCosSeries evaluates
+//
+//	sinp ? sum(c[i] * sin( 2*i    * x), i, 1, n) :
+//	       sum(c[i] * cos((2*i+1) * x), i, 0, n-1)
+//
+// using Clenshaw summation. c[0] is unused for the sine series.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64, n int) float64 {
+	k := n // one beyond the last element
+	if sinp {
+		k++
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx) // 2 * cos(2 * x)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	// Now n is even.
+	for n /= 2; n > 0; n-- {
+		// Unroll the loop x 2, so the accumulators return to their roles.
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0 // sin(2 * x) * y0
+	}
+	return cosx * (y0 - y1) // cos(x) * (y0 - y1)
+}
+
+// a1m1f returns the scale factor A1-1 = mean value of (d/dsigma)I1 - 1.
+func a1m1f(eps float64) float64 {
+	coeff := []float64{
+		// (1-eps)*A1-1, polynomial in eps2 of order 3
+		1, 4, 64, 0, 256,
+	}
+	m := nA1 / 2
+	t := polyval(m, coeff, sq(eps)) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// c1f sets c[1] through c[nC1] to the coefficients C1[l] in the Fourier
+// expansion of B1.
+func c1f(eps float64, c []float64) {
+	coeff := []float64{
+		// C1[1]/eps^1, polynomial in eps2 of order 2
+		-1, 6, -16, 32,
+		// C1[2]/eps^2, polynomial in eps2 of order 2
+		-9, 64, -128, 2048,
+		// C1[3]/eps^3, polynomial in eps2 of order 1
+		9, -16, 768,
+		// C1[4]/eps^4, polynomial in eps2 of order 1
+		3, -5, 512,
+		// C1[5]/eps^5, polynomial in eps2 of order 0
+		-7, 1280,
+		// C1[6]/eps^6, polynomial in eps2 of order 0
+		-7, 2048,
+	}
+	seriesCoeff(coeff, nC1, eps, c)
+}
+
+// c1pf sets c[1] through c[nC1p] to the coefficients C1p[l] in the
+// Fourier expansion of B1p, the inverse of B1.
+func c1pf(eps float64, c []float64) {
+	coeff := []float64{
+		// C1p[1]/eps^1, polynomial in eps2 of order 2
+		205, -432, 768, 1536,
+		// C1p[2]/eps^2, polynomial in eps2 of order 2
+		4005, -4736, 3840, 12288,
+		// C1p[3]/eps^3, polynomial in eps2 of order 1
+		-225, 116, 384,
+		// C1p[4]/eps^4, polynomial in eps2 of order 1
+		-7173, 2695, 7680,
+		// C1p[5]/eps^5, polynomial in eps2 of order 0
+		3467, 7680,
+		// C1p[6]/eps^6, polynomial in eps2 of order 0
+		38081, 61440,
+	}
+	seriesCoeff(coeff, nC1p, eps, c)
+}
+
+// a2m1f returns the scale factor A2-1 = mean value of (d/dsigma)I2 - 1.
+func a2m1f(eps float64) float64 {
+	coeff := []float64{
+		// (eps+1)*A2-1, polynomial in eps2 of order 3
+		-11, -28, -192, 0, 256,
+	}
+	m := nA2 / 2
+	t := polyval(m, coeff, sq(eps)) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// c2f sets c[1] through c[nC2] to the coefficients C2[l] in the Fourier
+// expansion of B2.
+func c2f(eps float64, c []float64) {
+	coeff := []float64{
+		// C2[1]/eps^1, polynomial in eps2 of order 2
+		1, 2, 16, 32,
+		// C2[2]/eps^2, polynomial in eps2 of order 2
+		35, 64, 384, 2048,
+		// C2[3]/eps^3, polynomial in eps2 of order 1
+		15, 80, 768,
+		// C2[4]/eps^4, polynomial in eps2 of order 1
+		7, 35, 512,
+		// C2[5]/eps^5, polynomial in eps2 of order 0
+		63, 1280,
+		// C2[6]/eps^6, polynomial in eps2 of order 0
+		77, 2048,
+	}
+	seriesCoeff(coeff, nC2, eps, c)
+}
+
+// seriesCoeff evaluates the coefficients of the expansions of B1, B1p
+// and B2, which are odd polynomials in eps.
+func seriesCoeff(coeff []float64, n int, eps float64, c []float64) {
+	eps2 := sq(eps)
+	d := eps
+	o := 0
+	for l := 1; l <= n; l++ {
+		m := (n - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// a3coeff sets the coefficients of the polynomial in eps for A3.
+func (g *geodesic) a3coeff() {
+	coeff := []float64{
+		// A3, coeff of eps^5, polynomial in n of order 0
+		-3, 128,
+		// A3, coeff of eps^4, polynomial in n of order 1
+		-2, -3, 64,
+		// A3, coeff of eps^3, polynomial in n of order 2
+		-1, -3, -1, 16,
+		// A3, coeff of eps^2, polynomial in n of order 2
+		3, -1, -2, 8,
+		// A3, coeff of eps^1, polynomial in n of order 1
+		1, -1, 2,
+		// A3, coeff of eps^0, polynomial in n of order 0
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := nA3 - 1; j >= 0; j-- {
+		m := nA3 - j - 1
+		if j < m {
+			m = j
+		}
+		g.a3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+// c3coeff sets the coefficients of the polynomials in eps for C3[l].
+func (g *geodesic) c3coeff() {
+	coeff := []float64{
+		// C3[1], coeff of eps^5, polynomial in n of order 0
+		3, 128,
+		// C3[1], coeff of eps^4, polynomial in n of order 1
+		2, 5, 128,
+		// C3[1], coeff of eps^3, polynomial in n of order 2
+		-1, 3, 3, 64,
+		// C3[1], coeff of eps^2, polynomial in n of order 2
+		-1, 0, 1, 8,
+		// C3[1], coeff of eps^1, polynomial in n of order 1
+		-1, 1, 4,
+		// C3[2], coeff of eps^5, polynomial in n of order 0
+		5, 256,
+		// C3[2], coeff of eps^4, polynomial in n of order 1
+		1, 3, 128,
+		// C3[2], coeff of eps^3, polynomial in n of order 2
+		-3, -2, 3, 64,
+		// C3[2], coeff of eps^2, polynomial in n of order 2
+		1, -3, 2, 32,
+		// C3[3], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[3], coeff of eps^4, polynomial in n of order 1
+		-10, 9, 384,
+		// C3[3], coeff of eps^3, polynomial in n of order 2
+		5, -9, 5, 192,
+		// C3[4], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[4], coeff of eps^4, polynomial in n of order 1
+		-14, 7, 512,
+		// C3[5], coeff of eps^5, polynomial in n of order 0
+		21, 2560,
+	}
+	o, k := 0, 0
+	for l := 1; l < nC3; l++ {
+		for j := nC3 - 1; j >= l; j-- {
+			m := nC3 - j - 1
+			if j < m {
+				m = j
+			}
+			g.c3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (g *geodesic) a3f(eps float64) float64 {
+	return polyval(nA3-1, g.a3x[:], eps)
+}
+
+// c3f sets c[1] through c[nC3-1] to the coefficients C3[l].
+func (g *geodesic) c3f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 1; l < nC3; l++ {
+		m := nC3 - l - 1 // order of polynomial in eps
+		mult *= eps
+		c[l] = mult * polyval(m, g.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+// c4coeff sets the coefficients of the polynomials in eps for C4[l].
+func (g *geodesic) c4coeff() {
+	coeff := []float64{
+		// C4[0], coeff of eps^5, polynomial in n of order 0
+		97, 15015,
+		// C4[0], coeff of eps^4, polynomial in n of order 1
+		1088, 156, 45045,
+		// C4[0], coeff of eps^3, polynomial in n of order 2
+		-224, -4784, 1573, 45045,
+		// C4[0], coeff of eps^2, polynomial in n of order 3
+		-10656, 14144, -4576, -858, 45045,
+		// C4[0], coeff of eps^1, polynomial in n of order 4
+		64, 624, -4576, 6864, -3003, 15015,
+		// C4[0], coeff of eps^0, polynomial in n of order 5
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		// C4[1], coeff of eps^5, polynomial in n of order 0
+		1, 9009,
+		// C4[1], coeff of eps^4, polynomial in n of order 1
+		-2944, 468, 135135,
+		// C4[1], coeff of eps^3, polynomial in n of order 2
+		5792, 1040, -1287, 135135,
+		// C4[1], coeff of eps^2, polynomial in n of order 3
+		5952, -11648, 9152, -2574, 135135,
+		// C4[1], coeff of eps^1, polynomial in n of order 4
+		-64, -624, 4576, -6864, 3003, 135135,
+		// C4[2], coeff of eps^5, polynomial in n of order 0
+		8, 10725,
+		// C4[2], coeff of eps^4, polynomial in n of order 1
+		1856, -936, 225225,
+		// C4[2], coeff of eps^3, polynomial in n of order 2
+		-8448, 4992, -1144, 225225,
+		// C4[2], coeff of eps^2, polynomial in n of order 3
+		-1440, 4160, -4576, 1716, 225225,
+		// C4[3], coeff of eps^5, polynomial in n of order 0
+		-136, 63063,
+		// C4[3], coeff of eps^4, polynomial in n of order 1
+		1024, -208, 105105,
+		// C4[3], coeff of eps^3, polynomial in n of order 2
+		3584, -3328, 1144, 315315,
+		// C4[4], coeff of eps^5, polynomial in n of order 0
+		-128, 135135,
+		// C4[4], coeff of eps^4, polynomial in n of order 1
+		-2560, 832, 405405,
+		// C4[5], coeff of eps^5, polynomial in n of order 0
+		128, 99099,
+	}
+	o, k := 0, 0
+	for l := 0; l < nC4; l++ {
+		for j := nC4 - 1; j >= l; j-- {
+			m := nC4 - j - 1
+			g.c4x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// c4f sets c[0] through c[nC4-1] to the coefficients C4[l].
+func (g *geodesic) c4f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 0; l < nC4; l++ {
+		m := nC4 - l - 1 // order of polynomial in eps
+		c[l] = mult * polyval(m, g.c4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+// lengths returns the distance s12b = s12/b, the reduced length
+// m12b = m12/b, the coefficient m0 of the secular term of the reduced
+// length, and the geodesic scales M12 and M21. Only the outputs that
+// are asked for are computed. ca is scratch space of size nC.
+func (g *geodesic) lengths(eps, sig12,
+	ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2 float64,
+	wantS, wantM, wantM0, wantScale bool, ca []float64) (s12b, m12b, m0, M12, M21 float64) {
+
+	var cb [nC]float64
+	var a1, a2, j12 float64
+
+	redlp := wantM || wantM0 || wantScale
+	if wantS || redlp {
+		a1 = a1m1f(eps)
+		c1f(eps, ca)
+		if redlp {
+			a2 = a2m1f(eps)
+			c2f(eps, cb[:])
+			m0 = a1 - a2
+			a2 = 1 + a2
+		}
+		a1 = 1 + a1
+	}
+	if wantS {
+		b1 := sinCosSeries(true, ssig2, csig2, ca, nC1) -
+			sinCosSeries(true, ssig1, csig1, ca, nC1)
+		// Missing a factor of b
+		s12b = a1 * (sig12 + b1)
+		if redlp {
+			b2 := sinCosSeries(true, ssig2, csig2, cb[:], nC2) -
+				sinCosSeries(true, ssig1, csig1, cb[:], nC2)
+			j12 = m0*sig12 + (a1*b1 - a2*b2)
+		}
+	} else if redlp {
+		// Assume here that nC1 >= nC2
+		for l := 1; l <= nC2; l++ {
+			cb[l] = a1*ca[l] - a2*cb[l]
+		}
+		j12 = m0*sig12 + (sinCosSeries(true, ssig2, csig2, cb[:], nC2) -
+			sinCosSeries(true, ssig1, csig1, cb[:], nC2))
+	}
+	if wantM {
+		// Missing a factor of b. The parentheses around (csig1 * ssig2)
+		// and (ssig1 * csig2) ensure accurate cancellation for
+		// coincident points.
+		m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	}
+	if wantScale {
+		csig12 := csig1*csig2 + ssig1*ssig2
+		t := g.ep2 * (cbet1 - cbet2) * (cbet1 + cbet2) / (dn1 + dn2)
+		M12 = csig12 + (t*ssig2-csig2*j12)*ssig1/dn1
+		M21 = csig12 - (t*ssig1-csig1*j12)*ssig2/dn2
+	}
+	return s12b, m12b, m0, M12, M21
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for the
+// positive root k.
+func astroid(x, y float64) float64 {
+	p := sq(x)
+	q := sq(y)
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		// For y small, positive root is k = abs(y)/sqrt(1-x^2)
+		return 0
+	}
+	// Avoid possible division by zero when r = 0 by multiplying the
+	// equations for s and t by r^3 and r, respectively.
+	S := p * q / 4 // S = r^3 * s
+	r2 := sq(r)
+	r3 := r * r2
+	// The discriminant of the quadratic equation for T3. This is zero on
+	// the evolute curve p^(1/3)+q^(1/3) = 1.
+	disc := S * (S + 2*r3)
+	u := r
+	if disc >= 0 {
+		T3 := S + r3
+		// Pick the sign on the sqrt to maximize abs(T3). This minimizes
+		// loss of precision due to cancellation.
+		if T3 < 0 {
+			T3 -= math.Sqrt(disc)
+		} else {
+			T3 += math.Sqrt(disc)
+		}
+		T := math.Cbrt(T3) // T = r * t
+		// T can be zero; but then r2 / T -> 0.
+		u += T
+		if T != 0 {
+			u += r2 / T
+		}
+	} else {
+		// T is complex, but the way u is defined the result is real.
+		ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+		// There are three possible cube roots. We choose the root which
+		// avoids cancellation. Note that disc < 0 implies that r < 0.
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(sq(u) + q) // guaranteed positive
+	// Avoid loss of accuracy when u < 0.
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v) // positive?
+	// Rearrange expression for k to avoid loss of accuracy due to
+	// subtraction. Division by 0 not possible because uv > 0, w >= 0.
+	return uv / (math.Sqrt(uv+sq(w)) + w) // guaranteed positive
+}
+
+// inverseStart returns a starting point for Newton's method in salp1
+// and calp1 (sig12 is -1). If Newton's method doesn't need to be used,
+// it also returns salp2, calp2 and sig12 >= 0.
+func (g *geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+	lam12, slam12, clam12 float64, ca []float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+
+	sig12 = -1
+	// bet12 = bet2 - bet1 in [0, pi); bet12a = bet2 + bet1 in (-pi, 0]
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := sq(sbet1 + sbet2)
+		// sin((bet1+bet2)/2)^2
+		// = (sbet1 + sbet2)^2 / ((sbet1 + sbet2)^2 + (cbet1 + cbet2)^2)
+		sbetm2 /= sbetm2 + sq(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*sq(somg12)/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+	}
+
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < g.etol2 {
+		// really short lines
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*(sq(somg12)/(1+comg12))
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		// Set return value
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(g.n) > 0.1 || // No astroid calc if too eccentric
+		csig12 >= 0 ||
+		ssig12 >= 6*math.Abs(g.n)*pi*sq(cbet1) {
+		// Nothing to do, zeroth order spherical approximation is OK
+	} else {
+		// Scale lam12 and bet2 to x, y coordinate system where antipodal
+		// point is at origin and singular point is at y = 0, x = -1.
+		var x, y, lamscale, betscale float64
+		lam12x := math.Atan2(-slam12, -clam12) // lam12 - pi
+		if g.f >= 0 {
+			// In fact f == 0 does not get here.
+			// x = dlong, y = dlat
+			k2 := sq(sbet1) * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			lamscale = g.f * cbet1 * g.a3f(eps) * pi
+			betscale = lamscale * cbet1
+			x = lam12x / lamscale
+			y = sbet12a / betscale
+		} else { // f < 0
+			// x = dlat, y = dlong
+			cbet12a := cbet2*cbet1 - sbet2*sbet1
+			bet12a := math.Atan2(sbet12a, cbet12a)
+			// In the case of lon12 = 180, this repeats a calculation
+			// made in genInverse.
+			_, m12b, m0, _, _ := g.lengths(g.n, pi+bet12a,
+				sbet1, -cbet1, dn1, sbet2, cbet2, dn2, cbet1, cbet2,
+				false, true, true, false, ca)
+			x = -1 + m12b/(cbet1*cbet2*m0*pi)
+			if x < -0.01 {
+				betscale = sbet12a / x
+			} else {
+				betscale = -g.f * sq(cbet1) * pi
+			}
+			lamscale = betscale / cbet1
+			y = lam12x / lamscale
+		}
+
+		if y > -tol1 && x > -1-xthresh {
+			// strip near cut
+			if g.f >= 0 {
+				salp1 = math.Min(1, -x)
+				calp1 = -math.Sqrt(1 - sq(salp1))
+			} else {
+				if x > -tol1 {
+					calp1 = math.Max(0, x)
+				} else {
+					calp1 = math.Max(-1, x)
+				}
+				salp1 = math.Sqrt(1 - sq(calp1))
+			}
+		} else {
+			// Estimate alp1, by solving the astroid problem.
+			k := astroid(x, y)
+			var omg12a float64
+			if g.f >= 0 {
+				omg12a = lamscale * (-x * k / (1 + k))
+			} else {
+				omg12a = lamscale * (-y * (1 + k) / k)
+			}
+			somg12, comg12 = math.Sin(omg12a), -math.Cos(omg12a)
+			// Update spherical estimate of alp1 using omg12 instead of
+			// lam12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+		}
+	}
+	// Sanity check on starting guess. Backwards check allows NaN through.
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+// lambda12 returns the longitude difference lam12 reached by the
+// geodesic leaving point 1 with azimuth alp1, relative to lam120, and
+// its derivative with respect to alp1 if diffp is set.
+func (g *geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+	salp1, calp1, slam120, clam120 float64, diffp bool, ca []float64) (lam12,
+	salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64) {
+
+	if sbet1 == 0 && calp1 == 0 {
+		// Break degeneracy of equatorial line. This case has already
+		// been handled.
+		calp1 = -tiny
+	}
+
+	// sin(alp1) * cos(bet1) = sin(alp0)
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1) // calp0 > 0
+
+	// tan(bet1) = tan(sig1) * cos(alp1)
+	// tan(omg1) = sin(alp0) * tan(sig1) = tan(omg1)=tan(alp1)*sin(bet1)
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+	// norm2(somg1, comg1); -- don't need to normalize!
+
+	// Enforce symmetries in the case abs(bet2) = -bet1. Need to be
+	// careful about this case, since this can yield singularities in
+	// the Newton iteration.
+	// sin(alp2) * cos(bet2) = sin(alp0)
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	// calp2 = sqrt(1 - sq(salp2))
+	//       = sqrt(sq(calp0) - sq(sbet2)) / cbet2
+	// and subst for calp0 and rearrange to give (choose positive sqrt
+	// to give alp2 in [0, pi/2]).
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var t float64
+		if cbet1 < -sbet1 {
+			t = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			t = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt(sq(calp1*cbet1)+t) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+	// tan(bet2) = tan(sig2) * cos(alp2)
+	// tan(omg2) = sin(alp0) * tan(sig2).
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+	// norm2(somg2, comg2); -- don't need to normalize!
+
+	// sig12 = sig2 - sig1, limit to [0, pi]
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+
+	// omg12 = omg2 - omg1, limit to [0, pi]
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2) + 0
+	comg12 := comg1*comg2 + somg1*somg2
+	// eta = omg12 - lam120
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := sq(calp0) * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, ca)
+	b312 := sinCosSeries(true, ssig2, csig2, ca, nC3-1) -
+		sinCosSeries(true, ssig1, csig1, ca, nC3-1)
+	domg12 = -g.f * g.a3f(eps) * salp0 * (sig12 + b312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _, _, _ = g.lengths(eps, sig12,
+				ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2,
+				false, true, false, false, ca)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	}
+	return
+}
+
+// genInverse solves the inverse geodesic problem between two points
+// given in degrees. It returns the arc length a12 in degrees, the
+// distance s12 in meters, the azimuths as sines and cosines, the
+// reduced length m12 in meters and the geodesic scales M12 and M21.
+// outmask selects which of s12, m12, M12 and M21 are computed.
+func (g *geodesic) genInverse(lat1, lon1, lat2, lon2 float64, outmask uint) (a12, s12,
+	salp1, calp1, salp2, calp2, m12, M12, M21, S12 float64) {
+
+	var ca [nC]float64
+	var s12x, m12x, sig12 float64
+	omg12, somg12, comg12 := 0.0, 2.0, 0.0
+	outmask &= outAll
+
+	// Compute longitude difference (angDiff does this carefully). Result
+	// is in [-180, 180] but -180 is only for west-going geodesics. 180 is
+	// for east-going and meridional geodesics.
+	lon12, lon12s := angDiff(lon1, lon2)
+	// Make longitude difference positive.
+	lonsign := 1.0
+	if math.Signbit(lon12) {
+		lonsign = -1
+	}
+	// If very close to being on the same half-meridian, then make it so.
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * degree
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosdx(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosdx(lon12)
+	}
+
+	// If really close to the equator, treat as on equator.
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	// Swap points so that point with higher (abs) latitude is point 1.
+	// If one latitude is a nan, then it becomes lat1.
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	// Make lat1 <= -0
+	latsign := -1.0
+	if math.Signbit(lat1) {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+	// Now we have
+	//
+	//     0 <= lon12 <= 180
+	//     -90 <= lat1 <= -0
+	//     lat1 <= lat2 <= -lat1
+	//
+	// lonsign, swapp, latsign register the transformation to bring the
+	// coordinates to this canonical form. In all cases, 1 means no change
+	// was made. We make these transformations so that there are few
+	// cases to check, e.g., on verifying quadrants in atan2. In addition,
+	// this enforces some symmetries in the results returned.
+
+	sbet1, cbet1 := sincosdx(lat1)
+	sbet1 *= g.f1
+	// Ensure cbet1 = +epsilon at poles
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+
+	sbet2, cbet2 := sincosdx(lat2)
+	sbet2 *= g.f1
+	// Ensure cbet2 = +epsilon at poles
+	sbet2, cbet2 = norm2(sbet2, cbet2)
+	cbet2 = math.Max(tiny, cbet2)
+
+	// If cbet1 < -sbet1, then cbet2 - cbet1 is a sensitive measure of
+	// the |bet1| - |bet2|. Alternatively (cbet1 >= -sbet1), abs(sbet2) +
+	// sbet1 is a better measure. This logic is used in assigning calp2
+	// in lambda12. Sometimes these quantities vanish and in that case we
+	// force bet2 = +/- bet1 exactly.
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else {
+		if math.Abs(sbet2) == -sbet1 {
+			cbet2 = cbet1
+		}
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sq(sbet1))
+	dn2 := math.Sqrt(1 + g.ep2*sq(sbet2))
+
+	wantScale := outmask&outGeodesicScale&outAll != 0
+
+	meridian := lat1 == -90 || slam12 == 0
+
+	if meridian {
+		// Endpoints are on a single full meridian, so the geodesic might
+		// lie on a meridian.
+		calp1, salp1 = clam12, slam12 // Head to the target longitude
+		calp2, salp2 = 1, 0           // At the target we're heading north
+
+		// tan(bet) = tan(sig) * cos(alp)
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+
+		// sig12 = sig2 - sig1
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _, M12, M21 = g.lengths(g.n, sig12,
+			ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2,
+			true, true, false, wantScale, ca[:])
+		// Add the check for sig12 since zero length geodesics might
+		// yield m12 < 0. In fact, we will have sig12 > pi/2 for
+		// meridional geodesic which is not a shortest path.
+		if sig12 < 1 || m12x >= 0 {
+			// Need at least 2, to handle 90 0 90 180
+			if sig12 < 3*tiny ||
+				// Prevent negative s12 or m12 for short lines
+				(sig12 < tol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+			a12 = sig12 / degree
+		} else {
+			// m12 < 0, i.e., prolate and too close to anti-podal
+			meridian = false
+		}
+	}
+
+	if !meridian &&
+		sbet1 == 0 && // and sbet2 == 0
+		// Mimic the way lambda12 works with calp1 = 0
+		(g.f <= 0 || lon12s >= g.f*180) {
+
+		// Geodesic runs along equator
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = g.a * lam12
+		sig12 = lam12 / g.f1
+		omg12 = sig12
+		m12x = g.b * math.Sin(sig12)
+		if wantScale {
+			M12 = math.Cos(sig12)
+			M21 = M12
+		}
+		a12 = lon12 / g.f1
+
+	} else if !meridian {
+		// Now point1 and point2 belong within a hemisphere bounded by a
+		// meridian and geodesic is neither meridional or equatorial.
+
+		// Figure a starting point for Newton's method
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1,
+			sbet2, cbet2, dn2, lam12, slam12, clam12, ca[:])
+
+		if sig12 >= 0 {
+			// Short lines (inverseStart sets salp2, calp2, dnm)
+			s12x = sig12 * g.b * dnm
+			m12x = sq(dnm) * g.b * math.Sin(sig12/dnm)
+			if wantScale {
+				M12 = math.Cos(sig12 / dnm)
+				M21 = M12
+			}
+			a12 = sig12 / degree
+			omg12 = lam12 / (g.f1 * dnm)
+		} else {
+			// Newton's method. This is a straightforward solution of
+			// f(alp1) = lambda12(alp1) - lam12 = 0 with one wrinkle.
+			// f(alp) has exactly one root in the interval (0, pi) and
+			// its derivative is positive at the root. Thus f(alp) is
+			// positive for alp > alp1 and negative for alp < alp1.
+			// During the course of the iteration, a range (alp1a, alp1b)
+			// is maintained which brackets the root and with each
+			// evaluation of f(alp) the range is shrunk, if possible.
+			// Newton's method is restarted whenever the derivative of f
+			// is negative (because the new value of alp1 is then further
+			// from the solution) or if the new estimate of alp1 lies
+			// outside (0,pi); in this case, the new starting guess is
+			// taken to be (alp1a + alp1b) / 2.
+			var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+			// Bracketing range
+			salp1a, calp1a := tiny, 1.0
+			salp1b, calp1b := tiny, -1.0
+			tripn, tripb := false, false
+			for numit := 0; ; numit++ {
+				// the WGS84 test set: mean = 1.47, sd = 1.25, max = 16
+				// WGS84 and random input: mean = 2.85, sd = 0.60
+				var v, dv float64
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv =
+					g.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+						salp1, calp1, slam12, clam12, numit < maxit1, ca[:])
+				tol := tol0
+				if tripn {
+					tol *= 8
+				}
+				if tripb ||
+					// Reversed test to allow escape with NaNs
+					!(math.Abs(v) >= tol) ||
+					// Enough bisections to get accurate result
+					numit == maxit2 {
+					break
+				}
+				// Update bracketing values
+				if v > 0 && (numit > maxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > maxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				if numit < maxit1 && dv > 0 {
+					dalp1 := -v / dv
+					if math.Abs(dalp1) < pi {
+						sdalp1, cdalp1 := math.Sin(dalp1), math.Cos(dalp1)
+						nsalp1 := salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = norm2(salp1, calp1)
+							// In some regimes we don't get quadratic
+							// convergence because slope -> 0. So use
+							// convergence conditions based on epsilon
+							// instead of sqrt(epsilon).
+							tripn = math.Abs(v) <= 16*tol0
+							continue
+						}
+					}
+				}
+				// Either dv was not positive or updated value was outside
+				// legal range. Use the midpoint of the bracket as the next
+				// estimate. This mechanism is not needed for the WGS84
+				// ellipsoid, but it does catch problems with more
+				// eccentric ellipsoids.
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm2(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < tolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < tolb
+			}
+			s12x, m12x, _, M12, M21 = g.lengths(eps, sig12,
+				ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2,
+				true, true, false, wantScale, ca[:])
+			m12x *= g.b
+			s12x *= g.b
+			a12 = sig12 / degree
+			if outmask&outArea != 0 {
+				// omg12 = lam12 - domg12
+				sdomg12, cdomg12 := math.Sin(domg12), math.Cos(domg12)
+				somg12 = slam12*cdomg12 - clam12*sdomg12
+				comg12 = clam12*cdomg12 + slam12*sdomg12
+			}
+		}
+	}
+
+	s12 = 0 + s12x // Convert -0 to 0
+	m12 = 0 + m12x // Convert -0 to 0
+
+	if outmask&outArea != 0 {
+		// From lambda12: sin(alp1) * cos(bet1) = sin(alp0)
+		salp0 := salp1 * cbet1
+		calp0 := math.Hypot(calp1, salp1*sbet1) // calp0 > 0
+		if calp0 != 0 && salp0 != 0 {
+			// From lambda12: tan(bet) = tan(sig) * cos(alp)
+			ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+			ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+			k2 := sq(calp0) * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			// Multiplier = a^2 * e^2 * cos(alpha0) * sin(alpha0).
+			A4 := sq(g.a) * calp0 * salp0 * g.e2
+			g.c4f(eps, ca[:])
+			B41 := sinCosSeries(false, ssig1, csig1, ca[:], nC4)
+			B42 := sinCosSeries(false, ssig2, csig2, ca[:], nC4)
+			S12 = A4 * (B42 - B41)
+		} else {
+			// Avoid problems with indeterminate sig1, sig2 on equator
+			S12 = 0
+		}
+
+		if !meridian && somg12 == 2 {
+			somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+		}
+
+		var alp12 float64
+		if !meridian &&
+			// omg12 < 3/4 * pi
+			comg12 > -0.7071 && // Long difference not too big
+			sbet2-sbet1 < 1.75 { // Lat difference not too big
+			// Use tan(Gamma/2) = tan(omg12/2)
+			// * (tan(bet1/2)+tan(bet2/2))/(1+tan(bet1/2)*tan(bet2/2))
+			// with tan(x/2) = sin(x)/(1+cos(x))
+			domg12, dbet1, dbet2 := 1+comg12, 1+cbet1, 1+cbet2
+			alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1),
+				domg12*(sbet1*sbet2+dbet1*dbet2))
+		} else {
+			// alp12 = alp2 - alp1, used in atan2 so no need to normalize
+			salp12 := salp2*calp1 - calp2*salp1
+			calp12 := calp2*calp1 + salp2*salp1
+			// The right thing appears to happen if alp1 = +/-180 and
+			// alp2 = 0, viz salp12 = -0 and alp12 = -180. However this
+			// depends on the sign being attached to 0 correctly. The
+			// following ensures the correct behavior.
+			if salp12 == 0 && calp12 < 0 {
+				salp12 = tiny * calp1
+				calp12 = -1
+			}
+			alp12 = math.Atan2(salp12, calp12)
+		}
+		S12 += g.c2 * alp12
+		S12 *= swapp * lonsign * latsign
+		// Convert -0 to 0
+		S12 += 0
+	}
+
+	// Convert calp, salp to azimuth accounting for lonsign, swapp,
+	// latsign.
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+		M12, M21 = M21, M12
+	}
+
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+
+	// Returned value in [0, 180]
+	return a12, s12, salp1, calp1, salp2, calp2, m12, M12, M21, S12
+}
+
+// inverse returns the distance in meters and the forward and back
+// azimuths in degrees between two points given in degrees.
+func (g *geodesic) inverse(lat1, lon1, lat2, lon2 float64) (s12, azi1, azi2 float64) {
+	_, s12, salp1, calp1, salp2, calp2, _, _, _, _ := g.genInverse(lat1, lon1, lat2, lon2, outDistance)
+	return s12, atan2dx(salp1, calp1), atan2dx(salp2, calp2)
+}
+
+// toKarney is To with Karney's inverse solver.
+func (ellipsoid Ellipsoid) toKarney(lat1, lon1, lat2, lon2 float64) (distance, bearing float64) {
+	if ellipsoid.Units == Radians {
+		lat1 = rad2deg(lat1)
+		lon1 = rad2deg(lon1)
+		lat2 = rad2deg(lat2)
+		lon2 = rad2deg(lon2)
+	}
+
+	s12, azi1, _ := cachedGeodesic(ellipsoid.Ellipse).inverse(lat1, lon1, lat2, lon2)
+
+	bearing = ellipsoid.adjustBearing(azi1, 180)
+	if ellipsoid.Units == Radians {
+		bearing = deg2rad(bearing)
+	}
+	distance = s12 / ellipsoid.DistanceFactor
+	return
+}
+
+// Mask selects the quantities computed by GenInverse and GenDirect.
+// Combine the constants with |.
+type Mask uint
+
+// The quantities that can be selected with a Mask.
+const (
+	// MaskLatitude selects the latitude Lat2 of GenDirect.
+	MaskLatitude Mask = outLatitude
+	// MaskLongitude selects the longitude Lon2 of GenDirect.
+	MaskLongitude Mask = outLongitude
+	// MaskAzimuth selects the azimuths Azi1 and Azi2.
+	MaskAzimuth Mask = outAzimuth
+	// MaskDistance selects the distance.
+	MaskDistance Mask = outDistance
+	// MaskReducedLength selects the reduced length m12.
+	MaskReducedLength Mask = outReducedLength
+	// MaskGeodesicScale selects the geodesic scales M12 and M21.
+	MaskGeodesicScale Mask = outGeodesicScale
+	// MaskArea selects the area S12 under the geodesic.
+	MaskArea Mask = outArea
+	// MaskAll selects everything.
+	MaskAll Mask = outAll | capAll
+)
+
+// GeodesicResult holds the solution of a geodesic problem. Angles are in
+// the Units of the Ellipsoid, lengths in its DistanceUnits and the area
+// in the square of its DistanceUnits. Quantities that were not selected
+// by the Mask are NaN.
+type GeodesicResult struct {
+	Lat1, Lon1 float64 // the first point
+	Azi1       float64 // the azimuth of the geodesic at the first point
+	Lat2, Lon2 float64 // the second point
+	Azi2       float64 // the azimuth of the geodesic at the second point
+
+	Distance float64 // the length of the geodesic, s12
+	Arc      float64 // the arc length on the auxiliary sphere, a12
+
+	ReducedLength   float64 // the reduced length of the geodesic, m12
+	GeodesicScale12 float64 // the geodesic scale of point 2 relative to point 1, M12
+	GeodesicScale21 float64 // the geodesic scale of point 1 relative to point 2, M21
+
+	// Area is the area between the geodesic from point 1 to point 2 and
+	// the equator, S12. It is counted positive for a geodesic running
+	// east with the equator to its right.
+	Area float64
+}
+
+/*
+Inverse solves the inverse geodesic problem between two points and
+returns all the quantities of GeodesicResult. The azimuths honor the
+BearingSymmetry of the Ellipsoid.
+
+	r := geo.Inverse(lat1, lon1, lat2, lon2)
+	backBearing := r.Azi2
+
+Inverse always uses Karney's method, whatever the GeodesicSolver.
+*/
+func (ellipsoid Ellipsoid) Inverse(lat1, lon1, lat2, lon2 float64) GeodesicResult {
+	return ellipsoid.GenInverse(lat1, lon1, lat2, lon2, MaskAll)
+}
+
+/*
+GenInverse is Inverse that computes only the quantities selected by mask.
+The arc length is always computed.
+
+	r := geo.GenInverse(lat1, lon1, lat2, lon2, ellipsoid.MaskDistance|ellipsoid.MaskArea)
+*/
+func (ellipsoid Ellipsoid) GenInverse(lat1, lon1, lat2, lon2 float64, mask Mask) GeodesicResult {
+	nan := math.NaN()
+	r := GeodesicResult{
+		Lat1: lat1, Lon1: lon1, Azi1: nan,
+		Lat2: lat2, Lon2: lon2, Azi2: nan,
+		Distance: nan, Arc: nan,
+		ReducedLength: nan, GeodesicScale12: nan, GeodesicScale21: nan,
+		Area: nan,
+	}
+	if ellipsoid.Units == Radians {
+		lat1 = rad2deg(lat1)
+		lon1 = rad2deg(lon1)
+		lat2 = rad2deg(lat2)
+		lon2 = rad2deg(lon2)
+	}
+
+	outmask := uint(mask) & outAll
+	a12, s12, salp1, calp1, salp2, calp2, m12, M12, M21, S12 :=
+		cachedGeodesic(ellipsoid.Ellipse).genInverse(lat1, lon1, lat2, lon2, outmask)
+
+	r.Arc = ellipsoid.fromDegrees(a12)
+	if outmask&outAzimuth != 0 {
+		r.Azi1 = ellipsoid.fromDegrees(ellipsoid.adjustBearing(atan2dx(salp1, calp1), 180))
+		r.Azi2 = ellipsoid.fromDegrees(ellipsoid.adjustBearing(atan2dx(salp2, calp2), 180))
+	}
+	if outmask&outDistance&outAll != 0 {
+		r.Distance = s12 / ellipsoid.DistanceFactor
+	}
+	if outmask&outReducedLength&outAll != 0 {
+		r.ReducedLength = m12 / ellipsoid.DistanceFactor
+	}
+	if outmask&outGeodesicScale&outAll != 0 {
+		r.GeodesicScale12 = M12
+		r.GeodesicScale21 = M21
+	}
+	if outmask&outArea&outAll != 0 {
+		r.Area = S12 / sq(ellipsoid.DistanceFactor)
+	}
+	return r
+}
+
+// fromDegrees converts an angle in degrees to the Units of the Ellipsoid.
+func (ellipsoid Ellipsoid) fromDegrees(x float64) float64 {
+	if ellipsoid.Units == Radians {
+		return deg2rad(x)
+	}
+	return x
+}
@@ -0,0 +1,272 @@
+package ellipsoid
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestKarneyInverse(t *testing.T) {
+	e, err := New("WGS84", WithGeodesicSolver(Karney), WithBearingSymmetry(BearingNotSymmetric))
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+
+	// JFK to LHR, from the GeographicLib documentation.
+	dist, bear := e.To(40.6, -73.8, 51.6, -0.5)
+	deltaWithin(t, loc(), dist, 5551759.400319, 1e-6)
+	deltaWithin(t, loc(), bear, 51.198882845, 1e-9)
+
+	// The nearly antipodal example of Karney (2013), table 5, where
+	// Vincenty's method fails.
+	dist, bear = e.To(-30, 0, 29.9, 179.8)
+	deltaWithin(t, loc(), dist, 19989832.82761, 1e-5)
+	deltaWithin(t, loc(), bear, 161.890524736, 1e-9)
+
+	// Exactly antipodal points on the equator and at the poles.
+	dist, _ = e.To(0, 0, 0, 180)
+	deltaWithin(t, loc(), dist, 20003931.4586, 1e-4)
+	dist, _ = e.To(90, 0, -90, 0)
+	deltaWithin(t, loc(), dist, 20003931.4586, 1e-4)
+	dist, bear = e.To(0, 0, 0, 0)
+	deltaWithin(t, loc(), dist, 0, 1e-9)
+
+	// The remaining cases agree with the existing tests of To.
+	allTests := []testobjectTo{
+		{loc(), -88.000000, 1.000000, -88.000000, 90.000000, 313115.736403696, 134.482545961512, 0.0001},
+		{loc(), -88.000000, 1.000000, 0.000000, 268.000000, 10013675.0566307, 266.991287566797, 0.0001},
+		{loc(), -88.000000, 90.000000, 88.000000, 268.000000, 19996176.9000454, 89.011158607592, 0.0001},
+		{loc(), 45.494490, 42.178744, -62.327500, 251.688039, 17356605.1087735, 214.442967212505, 0.0001}}
+	for _, v := range allTests {
+		dist, bear := e.To(v.lat1, v.lon1, v.lat2, v.lon2)
+		deltaWithin(t, v.loc, dist, v.d, 1e-1)
+		deltaWithin(t, v.loc, bear, v.b, v.tol)
+	}
+}
+
+func TestKarneyMatchesVincenty(t *testing.T) {
+	v := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingIsSymmetric)
+	k := v
+	k.GeodesicSolver = Karney
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		lat1 := (r.Float64()*178 - 89) * degree
+		lat2 := (r.Float64()*178 - 89) * degree
+		lon1 := (r.Float64()*360 - 180) * degree
+		lon2 := lon1 + (r.Float64()*300-150)*degree
+		d1, b1 := v.To(lat1, lon1, lat2, lon2)
+		d2, b2 := k.To(lat1, lon1, lat2, lon2)
+		deltaWithin(t, loc(), d2, d1, 1e-6)
+		deltaWithin(t, loc(), math.Remainder(b2-b1, twopi), 0, 1e-9)
+	}
+}
+
+func TestKarneyDirect(t *testing.T) {
+	e, err := New("WGS84", WithGeodesicSolver(Karney))
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+
+	// The direct example of Karney (2013), table 2.
+	lat2, lon2 := e.At(40, 0, 10000e3, 30)
+	deltaWithin(t, loc(), lat2, 41.79331020506, 1e-11)
+	deltaWithin(t, loc(), lon2, 137.84490004377, 1e-11)
+	_, _, azi2 := newGeodesic(e.Ellipse).direct(40, 0, 30, 10000e3)
+	deltaWithin(t, loc(), azi2, 149.09016931807, 1e-11)
+
+	// Starting at a pole the bearing is measured from the meridian.
+	lat2, lon2 = e.At(90, 0, 1000e3, 180)
+	deltaWithin(t, loc(), lon2, 0, 1e-11)
+	lat2, _ = e.At(-90, 0, 10001965.7293, 0)
+	deltaWithin(t, loc(), lat2, 0, 1e-9)
+
+	// Once and a half around the equator.
+	circ := twopi * e.Ellipse.Equatorial
+	lat2, lon2 = e.At(0, 10, 1.5*circ, 90)
+	deltaWithin(t, loc(), lat2, 0, 1e-9)
+	deltaWithin(t, loc(), lon2, -170, 1e-9)
+
+	// Ten times around a meridian returns to the start.
+	lat2, lon2 = e.At(10, 20, 10*4*10001965.729313, 0)
+	deltaWithin(t, loc(), lat2, 10, 1e-6)
+	deltaWithin(t, loc(), lon2, 20, 1e-9)
+}
+
+func TestKarneyDirectInverse(t *testing.T) {
+	e := Init("WGS84", Radians, Mile, LongitudeNotSymmetric, BearingIsSymmetric)
+	e.GeodesicSolver = Karney
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		lat1 := (r.Float64()*180 - 90) * degree
+		lon1 := r.Float64() * twopi
+		bear := (r.Float64()*360 - 180) * degree
+		dist := r.Float64() * 12000
+		lat2, lon2 := e.At(lat1, lon1, dist, bear)
+		if lon2 < 0 || lon2 >= twopi {
+			t.Errorf("At: longitude %v out of range", lon2)
+		}
+		d, _ := e.To(lat1, lon1, lat2, lon2)
+		deltaWithin(t, loc(), d, dist, 1e-8)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	e, err := New("WGS84", WithBearingSymmetry(BearingNotSymmetric))
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+
+	r := e.Inverse(10, 20, 40, 80)
+	d, b := e.To(10, 20, 40, 80)
+	deltaWithin(t, loc(), r.Distance, d, 1e-4)
+	deltaWithin(t, loc(), r.Azi1, b, 1e-8)
+	// Continuing along the geodesic from point 2 reaches it at Azi2.
+	_, _, azi2 := newGeodesic(e.Ellipse).direct(10, 20, r.Azi1, r.Distance)
+	deltaWithin(t, loc(), r.Azi2, azi2, 1e-9)
+	// The area agrees with a numerical integration along the geodesic.
+	deltaWithin(t, loc(), r.Area, 20031644106216, 1e4)
+
+	// m12 is the change of the position of point 2 per radian of
+	// change of the azimuth at point 1.
+	const dazi = 1e-4
+	latp, lonp := e.At(10, 20, r.Distance, r.Azi1+dazi/degree)
+	latm, lonm := e.At(10, 20, r.Distance, r.Azi1-dazi/degree)
+	shift, _ := e.To(latm, lonm, latp, lonp)
+	deltaWithin(t, loc(), r.ReducedLength, shift/(2*dazi), 1e-2)
+
+	// Along the equator the geodesic scales are cos(sigma12).
+	r = e.Inverse(0, 0, 0, 60)
+	deltaWithin(t, loc(), r.GeodesicScale12, math.Cos(r.Arc*degree), 1e-15)
+	deltaWithin(t, loc(), r.GeodesicScale21, math.Cos(r.Arc*degree), 1e-15)
+	deltaWithin(t, loc(), r.Area, 0, 1e-15)
+
+	// Reversing the direction flips the sign of the area.
+	r1 := e.Inverse(-30, 0, 50, -100)
+	r2 := e.Inverse(50, -100, -30, 0)
+	deltaWithin(t, loc(), r1.Area, -r2.Area, 1e-2)
+	deltaWithin(t, loc(), r1.GeodesicScale12, r2.GeodesicScale21, 1e-15)
+}
+
+func TestGenInverse(t *testing.T) {
+	e := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingIsSymmetric)
+	m := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingIsSymmetric)
+
+	r := e.GenInverse(10*degree, 20*degree, 40*degree, 80*degree, MaskDistance)
+	if !math.IsNaN(r.Area) || !math.IsNaN(r.Azi1) || !math.IsNaN(r.ReducedLength) {
+		t.Errorf("GenInverse: unselected quantities %v are not NaN", r)
+	}
+	rm := m.Inverse(10, 20, 40, 80)
+	deltaWithin(t, loc(), r.Distance, rm.Distance/1000, 1e-9)
+	deltaWithin(t, loc(), r.Arc, rm.Arc*degree, 1e-15)
+	if r.Lat1 != 10*degree || r.Lon2 != 80*degree {
+		t.Errorf("GenInverse: points are %v", r)
+	}
+
+	r = e.GenInverse(10*degree, 20*degree, 40*degree, 80*degree, MaskAzimuth|MaskArea|MaskReducedLength)
+	deltaWithin(t, loc(), r.Azi1, rm.Azi1*degree, 1e-12)
+	deltaWithin(t, loc(), r.Azi2, rm.Azi2*degree, 1e-12)
+	deltaWithin(t, loc(), r.ReducedLength, rm.ReducedLength/1000, 1e-9)
+	deltaWithin(t, loc(), r.Area, rm.Area/1e6, 1e-3)
+	if !math.IsNaN(r.GeodesicScale12) {
+		t.Errorf("GenInverse: unselected geodesic scale %v is not NaN", r.GeodesicScale12)
+	}
+}
+
+func TestDirect(t *testing.T) {
+	e, err := New("WGS84", WithBearingSymmetry(BearingNotSymmetric))
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+
+	// The direct example of Karney (2013), table 2.
+	r := e.Direct(40, 0, 30, 10000e3)
+	deltaWithin(t, loc(), r.Lat2, 41.79331020506, 1e-11)
+	deltaWithin(t, loc(), r.Lon2, 137.84490004377, 1e-11)
+	deltaWithin(t, loc(), r.Azi2, 149.09016931807, 1e-11)
+	deltaWithin(t, loc(), r.Arc, 89.92248718538, 1e-9)
+
+	// Direct and Inverse describe the same geodesic.
+	ri := e.Inverse(40, 0, r.Lat2, r.Lon2)
+	deltaWithin(t, loc(), r.ReducedLength, ri.ReducedLength, 1e-6)
+	deltaWithin(t, loc(), r.GeodesicScale12, ri.GeodesicScale12, 1e-12)
+	deltaWithin(t, loc(), r.GeodesicScale21, ri.GeodesicScale21, 1e-12)
+	deltaWithin(t, loc(), r.Area, ri.Area, 1)
+	deltaWithin(t, loc(), r.Arc, ri.Arc, 1e-12)
+
+	// Units and symmetry are honored.
+	k := Init("WGS84", Radians, Kilometer, LongitudeNotSymmetric, BearingIsSymmetric)
+	rk := k.Direct(40*degree, 0, 30*degree, 10000)
+	deltaWithin(t, loc(), rk.Lat2, r.Lat2*degree, 1e-14)
+	deltaWithin(t, loc(), rk.Lon2, r.Lon2*degree, 1e-14)
+	deltaWithin(t, loc(), rk.Azi2, r.Azi2*degree, 1e-14)
+	deltaWithin(t, loc(), rk.ReducedLength, r.ReducedLength/1000, 1e-9)
+	deltaWithin(t, loc(), rk.Area, r.Area/1e6, 1e-3)
+	rk = k.Direct(0, 0, -90*degree, 100)
+	if rk.Lon2 < pi || rk.Lon2 >= twopi {
+		t.Errorf("Direct: longitude %v is not in [0, 2pi)", rk.Lon2)
+	}
+
+	r = e.GenDirect(40, 0, 30, 10000e3, MaskLatitude|MaskLongitude)
+	deltaWithin(t, loc(), r.Lat2, 41.79331020506, 1e-11)
+	if !math.IsNaN(r.Azi2) || !math.IsNaN(r.Area) || !math.IsNaN(r.ReducedLength) || !math.IsNaN(r.Distance) {
+		t.Errorf("GenDirect: unselected quantities %v are not NaN", r)
+	}
+	if r = e.GenDirect(40, 0, 30, 10000e3, MaskDistance); r.Distance != 10000e3 {
+		t.Errorf("GenDirect: distance %v is not the input", r.Distance)
+	}
+}
+
+func TestGeodesicLine(t *testing.T) {
+	e, err := New("WGS84", WithBearingSymmetry(BearingNotSymmetric))
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+
+	line := e.Line(40, 0, 30)
+	for _, s := range []float64{-5000e3, 0, 1e3, 10000e3, 50000e3} {
+		lat2, lon2, azi2 := line.Position(s)
+		r := e.Direct(40, 0, 30, s)
+		deltaWithin(t, loc(), lat2, r.Lat2, 1e-12)
+		deltaWithin(t, loc(), lon2, r.Lon2, 1e-12)
+		deltaWithin(t, loc(), azi2, r.Azi2, 1e-12)
+	}
+	if !math.IsNaN(line.Distance()) {
+		t.Errorf("Line: Distance is %v, want NaN", line.Distance())
+	}
+
+	// JFK to LHR
+	line = e.InverseLine(40.6, -73.8, 51.6, -0.5)
+	deltaWithin(t, loc(), line.Distance(), 5551759.400319, 1e-6)
+	lat2, lon2, _ := line.Position(line.Distance())
+	deltaWithin(t, loc(), lat2, 51.6, 1e-12)
+	deltaWithin(t, loc(), lon2, -0.5, 1e-12)
+	lat2, lon2, _ = line.ArcPosition(line.Arc())
+	deltaWithin(t, loc(), lat2, 51.6, 1e-12)
+	deltaWithin(t, loc(), lon2, -0.5, 1e-12)
+	r := line.GenPosition(false, line.Distance()/2, MaskAll)
+	d, _ := e.To(40.6, -73.8, r.Lat2, r.Lon2)
+	deltaWithin(t, loc(), d, line.Distance()/2, 1e-4)
+	deltaWithin(t, loc(), r.Distance, line.Distance()/2, 1e-9)
+
+	// The units of the ellipsoid are used throughout.
+	k := Init("WGS84", Radians, Nm, LongitudeIsSymmetric, BearingIsSymmetric)
+	kline := k.InverseLine(40.6*degree, -73.8*degree, 51.6*degree, -0.5*degree)
+	deltaWithin(t, loc(), kline.Distance(), 5551759.400319/1852, 1e-9)
+	deltaWithin(t, loc(), kline.Arc(), line.Arc()*degree, 1e-14)
+	lat2, lon2, azi2 := kline.Position(kline.Distance())
+	deltaWithin(t, loc(), lat2, 51.6*degree, 1e-14)
+	deltaWithin(t, loc(), lon2, -0.5*degree, 1e-14)
+	_, _, azi := line.Position(line.Distance())
+	deltaWithin(t, loc(), azi2, azi*degree, 1e-14)
+}
+
+func TestCachedGeodesic(t *testing.T) {
+	e, _ := New("INTERNATIONAL")
+	g := cachedGeodesic(e.Ellipse)
+	if cachedGeodesic(e.Ellipse) != g {
+		t.Errorf("cachedGeodesic: geodesic not reused")
+	}
+	if *g != *newGeodesic(e.Ellipse) {
+		t.Errorf("cachedGeodesic: cached constants differ")
+	}
+}
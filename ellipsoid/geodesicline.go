@@ -0,0 +1,482 @@
+package ellipsoid
+
+import "math"
+
+// geodesicLine holds the constants of one geodesic through a point with
+// a given azimuth, so that positions along it can be computed quickly.
+// Only the coefficients selected by caps are computed.
+type geodesicLine struct {
+	lat1, lon1, azi1         float64
+	a, f, b, c2, f1, salp1   float64
+	calp1, dn1, salp0, calp0 float64
+	ssig1, csig1, somg1      float64
+	comg1, k2, a1m1, a2m1    float64
+	a3c, b11, b21, b31       float64
+	a4, b41                  float64
+	stau1, ctau1             float64
+
+	c1a  [nC1 + 1]float64
+	c1pa [nC1p + 1]float64
+	c2a  [nC2 + 1]float64
+	c3a  [nC3]float64
+	c4a  [nC4]float64
+
+	caps uint
+}
+
+// line returns the geodesic line from a point in degrees with the
+// azimuth azi1 in degrees.
+func (g *geodesic) line(lat1, lon1, azi1 float64, caps uint) *geodesicLine {
+	azi1 = angNormalize(azi1)
+	// Guard against underflow in salp0
+	salp1, calp1 := sincosdx(angRound(azi1))
+	return g.lineInt(lat1, lon1, azi1, salp1, calp1, caps)
+}
+
+func (g *geodesic) lineInt(lat1, lon1, azi1, salp1, calp1 float64, caps uint) *geodesicLine {
+	l := &geodesicLine{
+		a:  g.a,
+		f:  g.f,
+		b:  g.b,
+		c2: g.c2,
+		f1: g.f1,
+		// Always allow latitude and azimuth and unrolling of longitude.
+		caps: caps | outLatitude | outAzimuth | outLongUnroll,
+
+		lat1:  latFix(lat1),
+		lon1:  lon1,
+		azi1:  azi1,
+		salp1: salp1,
+		calp1: calp1,
+	}
+
+	sbet1, cbet1 := sincosdx(angRound(l.lat1))
+	sbet1 *= l.f1
+	// Ensure cbet1 = +epsilon at poles
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+	l.dn1 = math.Sqrt(1 + g.ep2*sq(sbet1))
+
+	// Evaluate alp0 from sin(alp1) * cos(bet1) = sin(alp0),
+	l.salp0 = l.salp1 * cbet1 // alp0 in [0, pi/2 - |bet1|]
+	// Alt: calp0 = hypot(sbet1, calp1 * cbet1). The following is
+	// slightly better (consider the case salp1 = 0).
+	l.calp0 = math.Hypot(l.calp1, l.salp1*sbet1)
+	// Evaluate sig with tan(bet1) = tan(sig1) * cos(alp1).
+	// sig = 0 is nearest northward crossing of equator.
+	// With bet1 = 0, alp1 = pi/2, we have sig1 = 0 (equatorial line).
+	// With bet1 =  pi/2, alp1 = -pi, sig1 =  pi/2
+	// With bet1 = -pi/2, alp1 =  0 , sig1 = -pi/2
+	// Evaluate omg1 with tan(omg1) = sin(alp0) * tan(sig1).
+	// With alp0 in (0, pi/2], quadrants for sig and omg coincide.
+	// No atan2(0,0) ambiguity at poles since cbet1 = +epsilon.
+	// With alp0 = 0, omg1 = 0 for alp1 = 0, omg1 = pi for alp1 = pi.
+	l.ssig1 = sbet1
+	l.somg1 = l.salp0 * sbet1
+	if sbet1 != 0 || l.calp1 != 0 {
+		l.csig1 = cbet1 * l.calp1
+	} else {
+		l.csig1 = 1
+	}
+	l.comg1 = l.csig1
+	l.ssig1, l.csig1 = norm2(l.ssig1, l.csig1) // sig1 in (-pi, pi]
+	// norm2(somg1, comg1); -- don't need to normalize!
+
+	l.k2 = sq(l.calp0) * g.ep2
+	eps := l.k2 / (2*(1+math.Sqrt(1+l.k2)) + l.k2)
+
+	if l.caps&capC1 != 0 {
+		l.a1m1 = a1m1f(eps)
+		c1f(eps, l.c1a[:])
+		l.b11 = sinCosSeries(true, l.ssig1, l.csig1, l.c1a[:], nC1)
+		s, c := math.Sin(l.b11), math.Cos(l.b11)
+		// tau1 = sig1 + B11
+		l.stau1 = l.ssig1*c + l.csig1*s
+		l.ctau1 = l.csig1*c - l.ssig1*s
+		// Not necessary because C1pa reverts C1a
+		//    B11 = -sinCosSeries(true, stau1, ctau1, C1pa, nC1p)
+	}
+
+	if l.caps&capC1p != 0 {
+		c1pf(eps, l.c1pa[:])
+	}
+
+	if l.caps&capC2 != 0 {
+		l.a2m1 = a2m1f(eps)
+		c2f(eps, l.c2a[:])
+		l.b21 = sinCosSeries(true, l.ssig1, l.csig1, l.c2a[:], nC2)
+	}
+
+	if l.caps&capC3 != 0 {
+		g.c3f(eps, l.c3a[:])
+		l.a3c = -l.f * l.salp0 * g.a3f(eps)
+		l.b31 = sinCosSeries(true, l.ssig1, l.csig1, l.c3a[:], nC3-1)
+	}
+
+	if l.caps&capC4 != 0 {
+		g.c4f(eps, l.c4a[:])
+		// Multiplier = a^2 * e^2 * cos(alpha0) * sin(alpha0)
+		l.a4 = sq(l.a) * l.calp0 * l.salp0 * g.e2
+		l.b41 = sinCosSeries(false, l.ssig1, l.csig1, l.c4a[:], nC4)
+	}
+	return l
+}
+
+// genPosition returns the point at the distance s12a12 in meters, or at
+// the arc length s12a12 in degrees if arcmode is set, from the start of
+// the line. outmask selects the outputs; it is limited by the
+// capabilities of the line. The returned a12 is the arc length in
+// degrees.
+func (l *geodesicLine) genPosition(arcmode bool, s12a12 float64, outmask uint) (a12,
+	lat2, lon2, azi2, s12, m12, M12, M21, S12 float64) {
+
+	outmask &= l.caps & (outAll | outLongUnroll)
+	if !(arcmode || l.caps&(outDistanceIn&outAll) != 0) {
+		// Impossible distance calculation requested
+		return math.NaN(), 0, 0, 0, 0, 0, 0, 0, 0
+	}
+
+	var sig12, ssig12, csig12, b12, ab1 float64
+	if arcmode {
+		// Interpret s12a12 as spherical arc length
+		sig12 = s12a12 * degree
+		ssig12, csig12 = sincosdx(s12a12)
+	} else {
+		// Interpret s12a12 as distance
+		tau12 := s12a12 / (l.b * (1 + l.a1m1))
+		s, c := math.Sin(tau12), math.Cos(tau12)
+		// tau2 = tau1 + tau12
+		b12 = -sinCosSeries(true, l.stau1*c+l.ctau1*s, l.ctau1*c-l.stau1*s, l.c1pa[:], nC1p)
+		sig12 = tau12 - (b12 - l.b11)
+		ssig12, csig12 = math.Sin(sig12), math.Cos(sig12)
+		if math.Abs(l.f) > 0.01 {
+			// Reverted distance series is inaccurate for |f| > 1/100, so
+			// correct sig12 with 1 Newton iteration.
+			ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+			csig2 := l.csig1*csig12 - l.ssig1*ssig12
+			b12 = sinCosSeries(true, ssig2, csig2, l.c1a[:], nC1)
+			serr := (1+l.a1m1)*(sig12+(b12-l.b11)) - s12a12/l.b
+			sig12 = sig12 - serr/math.Sqrt(1+l.k2*sq(ssig2))
+			ssig12, csig12 = math.Sin(sig12), math.Cos(sig12)
+			// Update b12 below
+		}
+	}
+
+	// sig2 = sig1 + sig12
+	ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+	csig2 := l.csig1*csig12 - l.ssig1*ssig12
+	dn2 := math.Sqrt(1 + l.k2*sq(ssig2))
+	if outmask&(outDistance|outReducedLength|outGeodesicScale) != 0 {
+		if arcmode || math.Abs(l.f) > 0.01 {
+			b12 = sinCosSeries(true, ssig2, csig2, l.c1a[:], nC1)
+		}
+		ab1 = (1 + l.a1m1) * (b12 - l.b11)
+	}
+	// sin(bet2) = cos(alp0) * sin(sig2)
+	sbet2 := l.calp0 * ssig2
+	// Alt: cbet2 = hypot(csig2, salp0 * ssig2);
+	cbet2 := math.Hypot(l.salp0, l.calp0*csig2)
+	if cbet2 == 0 {
+		// I.e., salp0 = 0, csig2 = 0. Break the degeneracy in this case
+		cbet2 = tiny
+		csig2 = tiny
+	}
+	// tan(alp0) = cos(sig2)*tan(alp2)
+	salp2, calp2 := l.salp0, l.calp0*csig2 // No need to normalize
+
+	if outmask&outDistance&outAll != 0 {
+		if arcmode {
+			s12 = l.b * ((1+l.a1m1)*sig12 + ab1)
+		} else {
+			s12 = s12a12
+		}
+	}
+
+	if outmask&outLongitude&outAll != 0 {
+		E := math.Copysign(1, l.salp0) // east or west going?
+		// tan(omg2) = sin(alp0) * tan(sig2)
+		somg2, comg2 := l.salp0*ssig2, csig2 // No need to normalize
+		// omg12 = omg2 - omg1
+		var omg12 float64
+		if outmask&outLongUnroll != 0 {
+			omg12 = E * (sig12 -
+				(math.Atan2(ssig2, csig2) - math.Atan2(l.ssig1, l.csig1)) +
+				(math.Atan2(E*somg2, comg2) - math.Atan2(E*l.somg1, l.comg1)))
+		} else {
+			omg12 = math.Atan2(somg2*l.comg1-comg2*l.somg1, comg2*l.comg1+somg2*l.somg1)
+		}
+		lam12 := omg12 + l.a3c*(sig12+(sinCosSeries(true, ssig2, csig2, l.c3a[:], nC3-1)-l.b31))
+		lon12 := lam12 / degree
+		if outmask&outLongUnroll != 0 {
+			lon2 = l.lon1 + lon12
+		} else {
+			lon2 = angNormalize(angNormalize(l.lon1) + angNormalize(lon12))
+		}
+	}
+
+	if outmask&outLatitude != 0 {
+		lat2 = atan2dx(sbet2, l.f1*cbet2)
+	}
+
+	if outmask&outAzimuth != 0 {
+		azi2 = atan2dx(salp2, calp2)
+	}
+
+	if outmask&(outReducedLength|outGeodesicScale)&outAll != 0 {
+		b22 := sinCosSeries(true, ssig2, csig2, l.c2a[:], nC2)
+		ab2 := (1 + l.a2m1) * (b22 - l.b21)
+		j12 := (l.a1m1-l.a2m1)*sig12 + (ab1 - ab2)
+		if outmask&outReducedLength&outAll != 0 {
+			// Add parens around (csig1 * ssig2) and (ssig1 * csig2) to
+			// ensure accurate cancellation in the case of coincident
+			// points.
+			m12 = l.b * ((dn2*(l.csig1*ssig2) - l.dn1*(l.ssig1*csig2)) - l.csig1*csig2*j12)
+		}
+		if outmask&outGeodesicScale&outAll != 0 {
+			t := l.k2 * (ssig2 - l.ssig1) * (ssig2 + l.ssig1) / (l.dn1 + dn2)
+			M12 = csig12 + (t*ssig2-csig2*j12)*l.ssig1/l.dn1
+			M21 = csig12 - (t*l.ssig1-l.csig1*j12)*ssig2/dn2
+		}
+	}
+
+	if outmask&outArea&outAll != 0 {
+		b42 := sinCosSeries(false, ssig2, csig2, l.c4a[:], nC4)
+		var salp12, calp12 float64
+		if l.calp0 == 0 || l.salp0 == 0 {
+			// alp12 = alp2 - alp1, used in atan2 so no need to normalize
+			salp12 = salp2*l.calp1 - calp2*l.salp1
+			calp12 = calp2*l.calp1 + salp2*l.salp1
+		} else {
+			// tan(alp) = tan(alp0) * sec(sig)
+			// tan(alp2-alp1) = (tan(alp2) -tan(alp1)) / (tan(alp2)*tan(alp1)+1)
+			// = calp0 * salp0 * (csig1-csig2) / (salp0^2 + calp0^2 * csig1*csig2)
+			// If csig12 > 0, write
+			//   csig1 - csig2 = ssig12 * (csig1 * ssig12 / (1 + csig12) + ssig1)
+			// else
+			//   csig1 - csig2 = csig1 * (1 - csig12) + ssig12 * ssig1
+			// No need to normalize
+			if csig12 <= 0 {
+				salp12 = l.csig1*(1-csig12) + ssig12*l.ssig1
+			} else {
+				salp12 = ssig12 * (l.csig1*ssig12/(1+csig12) + l.ssig1)
+			}
+			salp12 *= l.calp0 * l.salp0
+			calp12 = sq(l.salp0) + sq(l.calp0)*l.csig1*csig2
+		}
+		S12 = l.c2*math.Atan2(salp12, calp12) + l.a4*(b42-l.b41)
+	}
+
+	if arcmode {
+		a12 = s12a12
+	} else {
+		a12 = sig12 / degree
+	}
+	return a12, lat2, lon2, azi2, s12, m12, M12, M21, S12
+}
+
+// direct returns the point in degrees and the azimuth there at the
+// distance s12 in meters from a point with the azimuth azi1.
+func (g *geodesic) direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2 float64) {
+	l := g.line(lat1, lon1, azi1, outLatitude|outLongitude|outAzimuth|outDistanceIn)
+	_, lat2, lon2, azi2, _, _, _, _, _ = l.genPosition(false, s12, outLatitude|outLongitude|outAzimuth)
+	return lat2, lon2, azi2
+}
+
+// atKarney is At with Karney's direct solver.
+func (ellipsoid Ellipsoid) atKarney(lat1, lon1, distance, bearing float64) (lat2, lon2 float64) {
+	if ellipsoid.Units == Radians {
+		lat1 = rad2deg(lat1)
+		lon1 = rad2deg(lon1)
+		bearing = rad2deg(bearing)
+	}
+
+	lat2, lon2, _ = cachedGeodesic(ellipsoid.Ellipse).direct(lat1, lon1, bearing,
+		distance*ellipsoid.DistanceFactor)
+
+	lon2 = ellipsoid.adjustLongitude(lon2, 180)
+	if ellipsoid.Units == Radians {
+		lat2 = deg2rad(lat2)
+		lon2 = deg2rad(lon2)
+	}
+	return
+}
+
+/*
+Direct solves the direct geodesic problem: it returns the point at the
+distance s12 from the point lat1, lon1 in the direction azi1 together
+with the azimuth there, the arc length, the reduced length, the geodesic
+scales and the area of GeodesicResult. The results honor the Units,
+LongitudeSymmetric and BearingSymmetry of the Ellipsoid as At does.
+
+	r := geo.Direct(lat1, lon1, azi1, s12)
+	lat2, lon2, azi2 := r.Lat2, r.Lon2, r.Azi2
+
+Direct always uses Karney's method, whatever the GeodesicSolver.
+*/
+func (ellipsoid Ellipsoid) Direct(lat1, lon1, azi1, s12 float64) GeodesicResult {
+	return ellipsoid.GenDirect(lat1, lon1, azi1, s12, MaskAll)
+}
+
+/*
+GenDirect is Direct that computes only the quantities selected by mask;
+the others are NaN. The arc length is always computed, and the
+distance, if selected, is s12.
+
+	r := geo.GenDirect(lat1, lon1, azi1, s12, ellipsoid.MaskLatitude|ellipsoid.MaskLongitude)
+*/
+func (ellipsoid Ellipsoid) GenDirect(lat1, lon1, azi1, s12 float64, mask Mask) GeodesicResult {
+	r := ellipsoid.line(lat1, lon1, azi1, uint(mask)|outDistanceIn).GenPosition(false, s12, mask)
+	if uint(mask)&outDistance&outAll != 0 {
+		r.Distance = s12
+	}
+	return r
+}
+
+// GeodesicLine is a geodesic through a point with a given azimuth. The
+// series coefficients are computed once by Line or InverseLine, which
+// makes computing many positions along the geodesic cheap. Angles and
+// distances are in the units of the Ellipsoid the line was made with.
+type GeodesicLine struct {
+	ellipsoid Ellipsoid
+	line      *geodesicLine
+	s13, a13  float64 // length of an InverseLine in meters and degrees
+}
+
+/*
+Line returns the geodesic line starting at lat1, lon1 in the direction
+azi1.
+
+	line := geo.Line(lat1, lon1, azi1)
+	lat2, lon2, azi2 := line.Position(distance)
+*/
+func (ellipsoid Ellipsoid) Line(lat1, lon1, azi1 float64) GeodesicLine {
+	return ellipsoid.line(lat1, lon1, azi1, uint(MaskAll))
+}
+
+// line returns the geodesic line with the capabilities caps.
+func (ellipsoid Ellipsoid) line(lat1, lon1, azi1 float64, caps uint) GeodesicLine {
+	if ellipsoid.Units == Radians {
+		lat1 = rad2deg(lat1)
+		lon1 = rad2deg(lon1)
+		azi1 = rad2deg(azi1)
+	}
+	return GeodesicLine{
+		ellipsoid: ellipsoid,
+		line:      cachedGeodesic(ellipsoid.Ellipse).line(lat1, lon1, azi1, caps),
+		s13:       math.NaN(),
+		a13:       math.NaN(),
+	}
+}
+
+/*
+InverseLine returns the geodesic line through the points lat1, lon1 and
+lat2, lon2. Distance and Arc return the length of the line between the
+points.
+
+	line := geo.InverseLine(lat1, lon1, lat2, lon2)
+	lat, lon, _ := line.Position(line.Distance() / 2)
+*/
+func (ellipsoid Ellipsoid) InverseLine(lat1, lon1, lat2, lon2 float64) GeodesicLine {
+	if ellipsoid.Units == Radians {
+		lat1 = rad2deg(lat1)
+		lon1 = rad2deg(lon1)
+		lat2 = rad2deg(lat2)
+		lon2 = rad2deg(lon2)
+	}
+	g := cachedGeodesic(ellipsoid.Ellipse)
+	a12, s12, salp1, calp1, _, _, _, _, _, _ := g.genInverse(lat1, lon1, lat2, lon2, outDistance)
+	return GeodesicLine{
+		ellipsoid: ellipsoid,
+		line:      g.lineInt(lat1, lon1, atan2dx(salp1, calp1), salp1, calp1, uint(MaskAll)),
+		s13:       s12,
+		a13:       a12,
+	}
+}
+
+// Distance returns the length of an InverseLine. It is NaN for a Line.
+func (line GeodesicLine) Distance() float64 {
+	return line.s13 / line.ellipsoid.DistanceFactor
+}
+
+// Arc returns the arc length of an InverseLine. It is NaN for a Line.
+func (line GeodesicLine) Arc() float64 {
+	return line.ellipsoid.fromDegrees(line.a13)
+}
+
+/*
+Position returns the point at the given distance from the start of the
+line and the azimuth of the line there. The distance may be negative.
+
+	lat2, lon2, azi2 := line.Position(distance)
+*/
+func (line GeodesicLine) Position(distance float64) (lat2, lon2, azi2 float64) {
+	r := line.GenPosition(false, distance, MaskLatitude|MaskLongitude|MaskAzimuth)
+	return r.Lat2, r.Lon2, r.Azi2
+}
+
+/*
+ArcPosition returns the point at the arc length sigma on the auxiliary
+sphere from the start of the line and the azimuth of the line there.
+
+	lat2, lon2, azi2 := line.ArcPosition(sigma)
+*/
+func (line GeodesicLine) ArcPosition(sigma float64) (lat2, lon2, azi2 float64) {
+	r := line.GenPosition(true, sigma, MaskLatitude|MaskLongitude|MaskAzimuth)
+	return r.Lat2, r.Lon2, r.Azi2
+}
+
+/*
+GenPosition returns the quantities selected by mask for the point at the
+distance s12a12 from the start of the line, or at the arc length s12a12
+if arcmode is set.
+
+	r := line.GenPosition(false, distance, ellipsoid.MaskAll)
+*/
+func (line GeodesicLine) GenPosition(arcmode bool, s12a12 float64, mask Mask) GeodesicResult {
+	e := line.ellipsoid
+	l := line.line
+	nan := math.NaN()
+	r := GeodesicResult{
+		Lat1: e.fromDegrees(l.lat1), Lon1: e.fromDegrees(l.lon1),
+		Azi1: e.fromDegrees(e.adjustBearing(l.azi1, 180)),
+		Lat2: nan, Lon2: nan, Azi2: nan,
+		Distance: nan, Arc: nan,
+		ReducedLength: nan, GeodesicScale12: nan, GeodesicScale21: nan,
+		Area: nan,
+	}
+	if arcmode {
+		if e.Units == Radians {
+			s12a12 = rad2deg(s12a12)
+		}
+	} else {
+		s12a12 *= e.DistanceFactor
+	}
+
+	outmask := uint(mask) & outAll
+	a12, lat2, lon2, azi2, s12, m12, M12, M21, S12 := l.genPosition(arcmode, s12a12, outmask)
+
+	r.Arc = e.fromDegrees(a12)
+	if outmask&outLatitude != 0 {
+		r.Lat2 = e.fromDegrees(lat2)
+	}
+	if outmask&outLongitude&outAll != 0 {
+		r.Lon2 = e.fromDegrees(e.adjustLongitude(lon2, 180))
+	}
+	if outmask&outAzimuth != 0 {
+		r.Azi2 = e.fromDegrees(e.adjustBearing(azi2, 180))
+	}
+	if outmask&outDistance&outAll != 0 {
+		r.Distance = s12 / e.DistanceFactor
+	}
+	if outmask&outReducedLength&outAll != 0 {
+		r.ReducedLength = m12 / e.DistanceFactor
+	}
+	if outmask&outGeodesicScale&outAll != 0 {
+		r.GeodesicScale12 = M12
+		r.GeodesicScale21 = M21
+	}
+	if outmask&outArea&outAll != 0 {
+		r.Area = S12 / sq(e.DistanceFactor)
+	}
+	return r
+}
@@ -0,0 +1,150 @@
+package ellipsoid
+
+// The Lambert conformal conic projection, following EPSG Guidance Note
+// 7-2. The radius of a parallel on the cone is proportional to
+// exp(-n psi), where psi is the isometric latitude and n the cone
+// constant.
+
+import "math"
+
+// LambertConformalConic is a Lambert conformal conic grid with one or two
+// standard parallels, e.g. a US State Plane zone. Easting and northing
+// are in the distance units, angles in the units of the Ellipsoid.
+type LambertConformalConic struct {
+	ellipsoid Ellipsoid
+	lon0      float64 // the central meridian in radians
+	n         float64 // the cone constant
+	psi1, c   float64 // the radius is c exp(-n (psi - psi1)) in meters
+	r0        float64 // the radius of the parallel of the origin
+	fe, fn    float64 // meters
+}
+
+// parallelScale returns m = N cos(phi) / a, the radius of the parallel
+// phi in radians on the unit ellipsoid.
+func (e ellipse) parallelScale(phi float64) float64 {
+	return math.Cos(phi) / math.Sqrt(1-e.eccentricitySquared()*sq(math.Sin(phi)))
+}
+
+func (ellipsoid Ellipsoid) newLambertConformalConic(phi1, n, k0, phi0, lam0, falseEasting, falseNorthing float64) (LambertConformalConic, error) {
+	if !(n != 0 && math.Abs(n) <= 1) {
+		return LambertConformalConic{}, &OutOfRangeError{Param: "cone constant", Value: n}
+	}
+	if !(k0 > 0) || math.IsInf(k0, 1) {
+		return LambertConformalConic{}, &OutOfRangeError{Param: "scale factor", Value: k0}
+	}
+	if !(math.Abs(phi0) <= pi/2) {
+		return LambertConformalConic{}, &OutOfRangeError{Param: "latitude of false origin", Value: ellipsoid.angleOut(phi0)}
+	}
+	e := ellipsoid.Ellipse
+	p := LambertConformalConic{
+		ellipsoid: ellipsoid,
+		lon0:      lam0,
+		n:         n,
+		psi1:      e.isometricLatitude(phi1),
+		c:         e.Equatorial * k0 * e.parallelScale(phi1) / n,
+		fe:        falseEasting * ellipsoid.DistanceFactor,
+		fn:        falseNorthing * ellipsoid.DistanceFactor,
+	}
+	p.r0 = p.radius(phi0)
+	return p, nil
+}
+
+// radius returns the signed radius of the parallel phi in radians on
+// the cone in meters. It has the sign of n.
+func (p LambertConformalConic) radius(phi float64) float64 {
+	return p.c * math.Exp(-p.n*(p.ellipsoid.Ellipse.isometricLatitude(phi)-p.psi1))
+}
+
+/*
+NewLambertConformalConic1SP returns the Lambert conformal conic grid
+with one standard parallel (EPSG method 9801). The latitude of origin
+lat0 is the standard parallel with the scale k0; lon0 is the central
+meridian.
+
+	jamaica, err := clarke.NewLambertConformalConic1SP(18, -77, 1, 250000, 150000)
+	easting, northing, convergence, scale := jamaica.Forward(lat, lon)
+
+The error is an *OutOfRangeError if lat0 is the equator or a pole or if
+k0 is not positive.
+*/
+func (ellipsoid Ellipsoid) NewLambertConformalConic1SP(lat0, lon0, k0, falseEasting, falseNorthing float64) (LambertConformalConic, error) {
+	phi0 := ellipsoid.angleIn(lat0)
+	if !(math.Abs(phi0) < pi/2) {
+		return LambertConformalConic{}, &OutOfRangeError{Param: "latitude of origin", Value: lat0}
+	}
+	return ellipsoid.newLambertConformalConic(phi0, math.Sin(phi0), k0, phi0, ellipsoid.angleIn(lon0), falseEasting, falseNorthing)
+}
+
+/*
+NewLambertConformalConic2SP returns the Lambert conformal conic grid
+with the two standard parallels lat1 and lat2, which are true to scale
+(EPSG method 9802). The false easting and northing are those of the
+false origin latF, lonF.
+
+	texas, err := nad27.NewLambertConformalConic2SP(28+23.0/60, 30+17.0/60, 27+50.0/60, -99, 609601.22, 0)
+
+The error is an *OutOfRangeError if a standard parallel is a pole or if
+the parallels are symmetric about the equator, so that the cone is a
+cylinder.
+*/
+func (ellipsoid Ellipsoid) NewLambertConformalConic2SP(lat1, lat2, latF, lonF, falseEasting, falseNorthing float64) (LambertConformalConic, error) {
+	e := ellipsoid.Ellipse
+	phi1, phi2 := ellipsoid.angleIn(lat1), ellipsoid.angleIn(lat2)
+	for _, lat := range []float64{lat1, lat2} {
+		if !(math.Abs(ellipsoid.angleIn(lat)) < pi/2) {
+			return LambertConformalConic{}, &OutOfRangeError{Param: "standard parallel", Value: lat}
+		}
+	}
+	n := math.Sin(phi1)
+	if phi1 != phi2 {
+		n = (math.Log(e.parallelScale(phi1)) - math.Log(e.parallelScale(phi2))) /
+			(e.isometricLatitude(phi2) - e.isometricLatitude(phi1))
+	}
+	return ellipsoid.newLambertConformalConic(phi1, n, 1, ellipsoid.angleIn(latF), ellipsoid.angleIn(lonF), falseEasting, falseNorthing)
+}
+
+/*
+Forward returns the easting and northing of lat, lon, the convergence,
+which is the angle from true north to grid north, and the point scale.
+The scale is not defined at the pole.
+
+	easting, northing, convergence, scale := p.Forward(lat, lon)
+*/
+func (p LambertConformalConic) Forward(lat, lon float64) (easting, northing, convergence, scale float64) {
+	e := p.ellipsoid
+	phi := e.angleIn(lat)
+	theta := p.n * math.Remainder(e.angleIn(lon)-p.lon0, twopi)
+	r := p.radius(phi)
+	x := r * math.Sin(theta)
+	y := p.r0 - r*math.Cos(theta)
+	k := p.n * r / (e.Ellipse.Equatorial * e.Ellipse.parallelScale(phi))
+	f := e.DistanceFactor
+	return (x + p.fe) / f, (y + p.fn) / f, e.angleOut(theta), k
+}
+
+/*
+Reverse is the inverse of Forward. It returns the latitude and longitude
+of easting, northing with the convergence and the point scale there.
+
+	lat, lon, convergence, scale := p.Reverse(easting, northing)
+*/
+func (p LambertConformalConic) Reverse(easting, northing float64) (lat, lon, convergence, scale float64) {
+	e := p.ellipsoid
+	f := e.DistanceFactor
+	x := easting*f - p.fe
+	y := p.r0 - (northing*f - p.fn)
+	if p.n < 0 {
+		x, y = -x, -y
+	}
+	r := math.Copysign(math.Hypot(x, y), p.n)
+	theta := math.Atan2(x, y)
+	// The apex of the cone is the pole.
+	phi := math.Copysign(pi/2, p.n)
+	if r != 0 {
+		psi := p.psi1 - math.Log(r/p.c)/p.n
+		phi = math.Atan(tauf(math.Sinh(psi), e.Ellipse.eccentricity()))
+	}
+	k := p.n * r / (e.Ellipse.Equatorial * e.Ellipse.parallelScale(phi))
+	lam := e.adjustLongitude(math.Remainder(p.lon0+theta/p.n, twopi), pi)
+	return e.angleOut(phi), e.angleOut(lam), e.angleOut(theta), k
+}
@@ -0,0 +1,116 @@
+package ellipsoid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLambertConformalConic(t *testing.T) {
+	nad27, _ := New("NAD27")
+	dms := func(d, m, s float64) float64 { return d + m/60 + s/3600 }
+
+	// JAD69 / Jamaica National Grid, from EPSG Guidance Note 7-2.
+	jamaica, err := nad27.NewLambertConformalConic1SP(18, -77, 1, 250000, 150000)
+	if err != nil {
+		t.Fatalf("NewLambertConformalConic1SP: unexpected error %v", err)
+	}
+	easting, northing, _, scale := jamaica.Forward(dms(17, 55, 55.80), -dms(76, 56, 37.26))
+	deltaWithin(t, loc(), easting, 255966.58, 0.01)
+	deltaWithin(t, loc(), northing, 142493.51, 0.01)
+	lat, lon, _, scale2 := jamaica.Reverse(255966.58, 142493.51)
+	deltaWithin(t, loc(), lat, dms(17, 55, 55.80), 1e-7)
+	deltaWithin(t, loc(), lon, -dms(76, 56, 37.26), 1e-7)
+	deltaWithin(t, loc(), scale2, scale, 1e-9)
+	_, _, _, scale = jamaica.Forward(18, -70)
+	deltaWithin(t, loc(), scale, 1, 1e-15)
+
+	// NAD27 / Texas South Central, from EPSG Guidance Note 7-2. The false
+	// easting is 2000000 US survey feet.
+	const usft = 1200.0 / 3937
+	texas, err := nad27.NewLambertConformalConic2SP(dms(28, 23, 0), dms(30, 17, 0), dms(27, 50, 0), -99, 2000000*usft, 0)
+	if err != nil {
+		t.Fatalf("NewLambertConformalConic2SP: unexpected error %v", err)
+	}
+	easting, northing, convergence, _ := texas.Forward(28.5, -96)
+	deltaWithin(t, loc(), easting/usft, 2963503.91, 0.01)
+	deltaWithin(t, loc(), northing/usft, 254759.80, 0.01)
+	lat, lon, convergence2, _ := texas.Reverse(easting, northing)
+	deltaWithin(t, loc(), lat, 28.5, 1e-12)
+	deltaWithin(t, loc(), lon, -96, 1e-12)
+	deltaWithin(t, loc(), convergence2, convergence, 1e-12)
+
+	// The standard parallels are true to scale, the false origin is on
+	// the central meridian.
+	for _, lat := range []float64{dms(28, 23, 0), dms(30, 17, 0)} {
+		_, _, _, scale = texas.Forward(lat, -80)
+		deltaWithin(t, loc(), scale, 1, 1e-12)
+	}
+	easting, northing, convergence, _ = texas.Forward(dms(27, 50, 0), -99)
+	deltaWithin(t, loc(), easting, 2000000*usft, 1e-6)
+	deltaWithin(t, loc(), northing, 0, 1e-6)
+	deltaWithin(t, loc(), convergence, 0, 1e-15)
+
+	// The convergence and the scale agree with a short geodesic.
+	k, _ := New("NAD27", WithGeodesicSolver(Karney))
+	x1, y1, convergence, scale := texas.Forward(35, -110)
+	lat2, lon2 := k.At(35, -110, 1, 30)
+	x2, y2, _, _ := texas.Forward(lat2, lon2)
+	deltaWithin(t, loc(), (x2-x1)*(x2-x1)+(y2-y1)*(y2-y1), scale*scale, 1e-6)
+	deltaWithin(t, loc(), 30-convergence, rad2deg(math.Atan2(x2-x1, y2-y1)), 1e-5)
+
+	// The southern hemisphere and units.
+	r := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	south, err := r.NewLambertConformalConic2SP(-18*degree, -36*degree, 0, 134*degree, 0, 0)
+	if err != nil {
+		t.Fatalf("NewLambertConformalConic2SP: unexpected error %v", err)
+	}
+	for _, p := range [][2]float64{{-25, 134}, {-10, 150}, {-43, 113}, {0, 134}} {
+		easting, northing, convergence, scale := south.Forward(p[0]*degree, p[1]*degree)
+		lat, lon, convergence2, scale2 := south.Reverse(easting, northing)
+		deltaWithin(t, loc(), lat, p[0]*degree, 1e-14)
+		deltaWithin(t, loc(), lon, p[1]*degree, 1e-14)
+		deltaWithin(t, loc(), convergence2, convergence, 1e-14)
+		deltaWithin(t, loc(), scale2, scale, 1e-12)
+	}
+	_, _, _, scale = south.Forward(-36*degree, 120*degree)
+	deltaWithin(t, loc(), scale, 1, 1e-12)
+	_, northing, convergence, _ = south.Forward(-25*degree, 140*degree)
+	if northing > 0 || convergence > 0 {
+		t.Errorf("southern cone: northing %v, convergence %v", northing, convergence)
+	}
+
+	// The apex of the cone is the pole.
+	easting, northing, _, _ = south.Forward(-90*degree, 10*degree)
+	lat, _, _, _ = south.Reverse(easting, northing)
+	deltaWithin(t, loc(), lat, -90*degree, 1e-15)
+	easting, northing, _, _ = texas.Forward(90, 10)
+	lat, lon, _, _ = texas.Reverse(easting, northing)
+	deltaWithin(t, loc(), lat, 90, 1e-12)
+	deltaWithin(t, loc(), lon, -99, 1e-12)
+}
+
+func TestLambertConformalConicErrors(t *testing.T) {
+	nad27, _ := New("NAD27")
+	var outOfRange *OutOfRangeError
+	for _, c := range []struct {
+		param string
+		err   error
+	}{
+		{"cone constant", lambertError(nad27.NewLambertConformalConic1SP(0, -77, 1, 0, 0))},
+		{"latitude of origin", lambertError(nad27.NewLambertConformalConic1SP(90, -77, 1, 0, 0))},
+		{"scale factor", lambertError(nad27.NewLambertConformalConic1SP(18, -77, 0, 0, 0))},
+		{"cone constant", lambertError(nad27.NewLambertConformalConic2SP(30, -30, 0, -99, 0, 0))},
+		{"standard parallel", lambertError(nad27.NewLambertConformalConic2SP(30, -90, 0, -99, 0, 0))},
+		{"latitude of false origin", lambertError(nad27.NewLambertConformalConic2SP(30, 40, 100, -99, 0, 0))},
+	} {
+		if !errors.As(c.err, &outOfRange) || outOfRange.Param != c.param {
+			t.Errorf("expected OutOfRangeError for the %s, got %v", c.param, c.err)
+		}
+	}
+}
+
+// second returns the error of a constructor.
+func lambertError(_ LambertConformalConic, err error) error {
+	return err
+}
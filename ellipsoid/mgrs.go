@@ -0,0 +1,316 @@
+package ellipsoid
+
+// The Military Grid Reference System and the US National Grid. A grid
+// reference names the UTM zone and latitude band, or the UPS band, the
+// 100 km square and the easting and northing within the square. This
+// follows the MGRS code of GeographicLib.
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	mgrsTile         = 100e3 // the size of a square in meters
+	mgrsMaxPrecision = 5     // 1 m
+	mgrsLatBands     = "CDEFGHJKLMNPQRSTUVWX"
+	mgrsUTMRows      = "ABCDEFGHJKLMNPQRSTUV"
+	mgrsUPSBands     = "ABYZ"
+	mgrsUTMRowPeriod = 20
+	mgrsEvenRowShift = 5   // for even zones
+	mgrsOldRowShift  = 10  // for the old ellipsoids
+	mgrsUPSEasting   = 20  // the first square east of the pole
+	mgrsMinUPSSouth  = 8   // the first square of the south polar grid
+	mgrsMinUPSNorth  = 13  // the first square of the north polar grid
+	mgrsBandMargin   = 0.9 // degrees, about 100 km
+)
+
+var (
+	mgrsUTMCols = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+	mgrsUPSCols = [4]string{"JKLPQRSTUXYZ", "ABCFGHJKLPQR", "RSTUXYZ", "ABCFGHJ"}
+	mgrsUPSRows = [2]string{"ABCDEFGHJKLMNPQRSTUVWXYZ", "ABCDEFGHJKLMNP"}
+)
+
+// mgrsOldEllipses use the old lettering of the rows of the 100 km
+// squares, which is shifted by 10 letters.
+var mgrsOldEllipses = []string{"BESSEL-1841", "BESSEL-1841-NAMIBIA", "CLARKE-1866", "CLARKE-1880", "NAD27"}
+
+// oldMGRSLettering reports whether e is one of mgrsOldEllipses.
+func (e ellipse) oldMGRSLettering() bool {
+	for _, name := range mgrsOldEllipses {
+		if old, ok := lookup(name); ok && old == e {
+			return true
+		}
+	}
+	return false
+}
+
+// mgrsRef is a parsed grid reference. The easting and the northing are
+// the digits within the square.
+type mgrsRef struct {
+	zone              int // 0 for UPS
+	band, col, row    byte
+	easting, northing int
+	precision         int
+}
+
+func (r mgrsRef) digits() string {
+	if r.precision == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%0*d%0*d", r.precision, r.easting, r.precision, r.northing)
+}
+
+func (r mgrsRef) String() string {
+	s := string([]byte{r.band, r.col, r.row}) + r.digits()
+	if r.zone != UPSZone {
+		s = fmt.Sprintf("%02d", r.zone) + s
+	}
+	return s
+}
+
+// usng returns the reference in the spaced format of the US National
+// Grid.
+func (r mgrsRef) usng() string {
+	s := string([]byte{r.band, ' ', r.col, r.row})
+	if r.zone != UPSZone {
+		s = fmt.Sprint(r.zone) + s
+	}
+	if d := r.digits(); d != "" {
+		s += " " + d[:r.precision] + " " + d[r.precision:]
+	}
+	return s
+}
+
+// mgrs returns the grid reference of lat, lon with precision digits
+// for each of easting and northing.
+func (ellipsoid Ellipsoid) mgrs(lat, lon float64, precision int) (mgrsRef, error) {
+	if precision < 0 || precision > mgrsMaxPrecision {
+		return mgrsRef{}, &OutOfRangeError{Param: "MGRS precision", Value: float64(precision)}
+	}
+	u, err := ellipsoid.ToUTMUPS(lat, lon)
+	if err != nil {
+		return mgrsRef{}, err
+	}
+	x := u.Easting * ellipsoid.DistanceFactor
+	y := u.Northing * ellipsoid.DistanceFactor
+	xh := int(math.Floor(x / mgrsTile))
+	yh := int(math.Floor(y / mgrsTile))
+	r := mgrsRef{zone: u.Zone, precision: precision}
+
+	if u.Zone != UPSZone {
+		latd := lat
+		if ellipsoid.Units == Radians {
+			latd = rad2deg(lat)
+		}
+		cols := mgrsUTMCols[(u.Zone-1)%3]
+		if xh < 1 || xh > len(cols) {
+			return mgrsRef{}, &OutOfRangeError{Param: "MGRS easting", Value: u.Easting}
+		}
+		shift := 0
+		if u.Zone%2 == 0 {
+			shift += mgrsEvenRowShift
+		}
+		if ellipsoid.Ellipse.oldMGRSLettering() {
+			shift += mgrsOldRowShift
+		}
+		r.band = mgrsLatBands[latitudeBand(latd)+10]
+		r.col = cols[xh-1]
+		r.row = mgrsUTMRows[(yh+shift)%mgrsUTMRowPeriod]
+	} else {
+		east := xh >= mgrsUPSEasting
+		i := 0
+		if u.North {
+			i += 2
+		}
+		if east {
+			i++
+		}
+		first := mgrsMinUPSSouth
+		if u.North {
+			first = mgrsMinUPSNorth
+		}
+		col := xh - first
+		if east {
+			col = xh - mgrsUPSEasting
+		}
+		rows := mgrsUPSRows[i/2]
+		if col < 0 || col >= len(mgrsUPSCols[i]) || yh-first < 0 || yh-first >= len(rows) {
+			return mgrsRef{}, &OutOfRangeError{Param: "latitude", Value: lat}
+		}
+		r.band = mgrsUPSBands[i]
+		r.col = mgrsUPSCols[i][col]
+		r.row = rows[yh-first]
+	}
+
+	unit := math.Pow10(mgrsMaxPrecision - precision)
+	r.easting = int(math.Floor((x - float64(xh)*mgrsTile) / unit))
+	r.northing = int(math.Floor((y - float64(yh)*mgrsTile) / unit))
+	return r, nil
+}
+
+/*
+ToMGRS returns the MGRS grid reference of lat, lon. The precision is the
+number of digits of the easting and of the northing, from 0 for the
+100 km square and 1 for 10 km to 5 for 1 m. The digits are truncated, so
+the reference names the square that contains the location. The old
+lettering of the rows is used for the Bessel 1841 and Clarke ellipsoids.
+
+	ref, err := geo.ToMGRS(lat, lon, 5) // e.g. "18SUJ2337106519"
+*/
+func (ellipsoid Ellipsoid) ToMGRS(lat, lon float64, precision int) (string, error) {
+	r, err := ellipsoid.mgrs(lat, lon, precision)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+/*
+ToUSNG is ToMGRS in the format of the US National Grid, which separates
+the zone, the square, the easting and the northing by spaces.
+
+	ref, err := geo.ToUSNG(lat, lon, 5) // e.g. "18S UJ 23371 06519"
+*/
+func (ellipsoid Ellipsoid) ToUSNG(lat, lon float64, precision int) (string, error) {
+	r, err := ellipsoid.mgrs(lat, lon, precision)
+	if err != nil {
+		return "", err
+	}
+	return r.usng(), nil
+}
+
+// parseMGRS splits an MGRS or USNG reference into its parts.
+func parseMGRS(ref string) (mgrsRef, error) {
+	s := strings.ToUpper(strings.Join(strings.Fields(ref), ""))
+	fail := func(reason string) (mgrsRef, error) {
+		return mgrsRef{}, &InvalidMGRSError{Ref: ref, Reason: reason}
+	}
+	var r mgrsRef
+	i := 0
+	for i < len(s) && i < 3 && s[i] >= '0' && s[i] <= '9' {
+		r.zone = 10*r.zone + int(s[i]-'0')
+		i++
+	}
+	switch {
+	case i > 2:
+		return fail("zone too long")
+	case i > 0 && (r.zone < utmMinZone || r.zone > utmMaxZone):
+		return fail("zone out of range")
+	case len(s) < i+3:
+		return fail("too short")
+	}
+	r.band, r.col, r.row = s[i], s[i+1], s[i+2]
+	bands := mgrsLatBands
+	if r.zone == UPSZone {
+		bands = mgrsUPSBands
+	}
+	if strings.IndexByte(bands, r.band) < 0 {
+		return fail("invalid band " + string(r.band))
+	}
+	d := s[i+3:]
+	if len(d)%2 != 0 || len(d) > 2*mgrsMaxPrecision {
+		return fail("odd or too many digits")
+	}
+	r.precision = len(d) / 2
+	for j := 0; j < len(d); j++ {
+		if d[j] < '0' || d[j] > '9' {
+			return fail("invalid digit " + string(d[j]))
+		}
+		if j < r.precision {
+			r.easting = 10*r.easting + int(d[j]-'0')
+		} else {
+			r.northing = 10*r.northing + int(d[j]-'0')
+		}
+	}
+	return r, nil
+}
+
+/*
+FromMGRS is the inverse of ToMGRS. It returns the latitude and longitude
+of the centre of the square named by an MGRS or a USNG grid reference.
+Spaces and lower case letters are accepted.
+
+	lat, lon, err := geo.FromMGRS("18SUJ2337106519")
+*/
+func (ellipsoid Ellipsoid) FromMGRS(ref string) (lat, lon float64, err error) {
+	r, err := parseMGRS(ref)
+	if err != nil {
+		return 0, 0, err
+	}
+	invalid := func(reason string) (float64, float64, error) {
+		return 0, 0, &InvalidMGRSError{Ref: ref, Reason: reason}
+	}
+
+	var xh, yh int
+	north := true
+	if r.zone != UPSZone {
+		band := strings.IndexByte(mgrsLatBands, r.band) - 10
+		north = band >= 0
+		xh = strings.IndexByte(mgrsUTMCols[(r.zone-1)%3], r.col) + 1
+		row := strings.IndexByte(mgrsUTMRows, r.row)
+		if xh == 0 || row < 0 {
+			return invalid("invalid 100 km square")
+		}
+		if r.zone%2 == 0 {
+			row -= mgrsEvenRowShift
+		}
+		if ellipsoid.Ellipse.oldMGRSLettering() {
+			row -= mgrsOldRowShift
+		}
+		// The row repeats every 2000 km; take the first one north of the
+		// southern edge of the band, with a margin for the curvature of
+		// the parallel.
+		tm := cachedTransverseMercator(ellipsoid.Ellipse, utmK0)
+		_, ymin, _, _ := tm.forward(0, float64(-80+8*(band+10))*degree, 0)
+		if !north {
+			ymin += utmFalseNorthing
+		}
+		base := int(math.Floor(ymin/mgrsTile)) - 1
+		yh = base + ((row-base)%mgrsUTMRowPeriod+mgrsUTMRowPeriod)%mgrsUTMRowPeriod
+	} else {
+		i := strings.IndexByte(mgrsUPSBands, r.band)
+		north = i >= 2
+		col := strings.IndexByte(mgrsUPSCols[i], r.col)
+		row := strings.IndexByte(mgrsUPSRows[i/2], r.row)
+		if col < 0 || row < 0 {
+			return invalid("invalid 100 km square")
+		}
+		first := mgrsMinUPSSouth
+		if north {
+			first = mgrsMinUPSNorth
+		}
+		xh, yh = col+first, row+first
+		if i%2 == 1 {
+			xh = col + mgrsUPSEasting
+		}
+	}
+
+	unit := math.Pow10(mgrsMaxPrecision - r.precision)
+	x := float64(xh)*mgrsTile + (float64(r.easting)+0.5)*unit
+	y := float64(yh)*mgrsTile + (float64(r.northing)+0.5)*unit
+	f := ellipsoid.DistanceFactor
+	lat, lon, err = ellipsoid.FromUTMUPS(r.zone, north, x/f, y/f)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if r.zone != UPSZone {
+		latd := lat
+		if ellipsoid.Units == Radians {
+			latd = rad2deg(lat)
+		}
+		// A square may straddle the edge of its band.
+		band := strings.IndexByte(mgrsLatBands, r.band) - 10
+		latmin := float64(-80 + 8*(band+10))
+		latmax := latmin + 8
+		if band == 9 {
+			latmax = utmMaxLatitude
+		}
+		if latd < latmin-mgrsBandMargin || latd > latmax+mgrsBandMargin {
+			return invalid("the square is not in band " + string(r.band))
+		}
+	}
+	return lat, lon, nil
+}
@@ -0,0 +1,108 @@
+package ellipsoid
+
+import (
+	"testing"
+)
+
+func TestToMGRS(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	for _, c := range []struct {
+		lat, lon  float64
+		precision int
+		mgrs      string
+		usng      string
+	}{
+		{33.3, 44.4, 2, "38SMB4484", "38S MB 44 84"},
+		{33.3, 44.4, 5, "38SMB4414084706", "38S MB 44140 84706"},
+		{33.3, 44.4, 0, "38SMB", "38S MB"},
+		{0, 0, 3, "31NAA660000", "31N AA 660 000"},
+		{60, 5, 1, "32VKM75", "32V KM 7 5"},
+		{78, 15, 4, "33XWG00005836", "33X WG 0000 5836"},
+		{-33.9, 18.4, 5, "34HBH5958345888", "34H BH 59583 45888"},
+		{5, -153, 3, "05NNF000526", "5N NF 000 526"},
+		{90, 0, 5, "ZAH0000000000", "Z AH 00000 00000"},
+		{-90, 0, 5, "BAN0000000000", "B AN 00000 00000"},
+		{85, 100, 3, "ZHH470964", "Z HH 470 964"},
+		{-85, -100, 3, "ASM529035", "A SM 529 035"},
+	} {
+		if got, err := e.ToMGRS(c.lat, c.lon, c.precision); err != nil || got != c.mgrs {
+			t.Errorf("ToMGRS(%v, %v, %d) = %q, %v, want %q", c.lat, c.lon, c.precision, got, err, c.mgrs)
+		}
+		if got, err := e.ToUSNG(c.lat, c.lon, c.precision); err != nil || got != c.usng {
+			t.Errorf("ToUSNG(%v, %v, %d) = %q, %v, want %q", c.lat, c.lon, c.precision, got, err, c.usng)
+		}
+	}
+
+	// The old lettering shifts the rows by 10 letters.
+	c, _ := New("CLARKE-1866")
+	if got, _ := c.ToMGRS(33.3, 44.4, 0); got != "38SMM" {
+		t.Errorf("ToMGRS on CLARKE-1866 = %q, want 38SMM", got)
+	}
+
+	for _, p := range []int{-1, 6} {
+		if _, err := e.ToMGRS(0, 0, p); err == nil {
+			t.Errorf("ToMGRS with precision %d did not fail", p)
+		}
+	}
+
+	r := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	if got, _ := r.ToMGRS(33.3*degree, 44.4*degree, 2); got != "38SMB4484" {
+		t.Errorf("ToMGRS in radians = %q", got)
+	}
+}
+
+func TestFromMGRS(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	// The centre of the square.
+	lat, lon, err := e.FromMGRS("18SUJ2337106519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := e.ToUTM(lat, lon)
+	deltaWithin(t, loc(), u.Easting, 323371.5, 1e-6)
+	deltaWithin(t, loc(), u.Northing, 4306519.5, 1e-6)
+	for _, ref := range []string{"18S UJ 23371 06519", "18suj2337106519", " 18SUJ 2337106519 "} {
+		lat2, lon2, err := e.FromMGRS(ref)
+		if err != nil || lat2 != lat || lon2 != lon {
+			t.Errorf("FromMGRS(%q) = %v, %v, %v", ref, lat2, lon2, err)
+		}
+	}
+
+	// Round trips across the grid.
+	for lat := -89.45; lat < 90; lat += 3.7 {
+		for lon := -179.5; lon < 180; lon += 11.3 {
+			ref, err := e.ToMGRS(lat, lon, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lat2, lon2, err := e.FromMGRS(ref)
+			if err != nil {
+				t.Fatalf("FromMGRS(%q): %v", ref, err)
+			}
+			if ref2, _ := e.ToMGRS(lat2, lon2, 5); ref2 != ref {
+				t.Errorf("FromMGRS(%q) is in %q", ref, ref2)
+			}
+			deltaWithin(t, loc(), lat2, lat, 2e-5)
+			if lat < 89 && lat > -89 {
+				deltaWithin(t, loc(), lon2, lon, 2e-4)
+			}
+		}
+	}
+
+	c, _ := New("BESSEL-1841")
+	ref, _ := c.ToMGRS(52.5, 13.4, 5)
+	lat, lon, _ = c.FromMGRS(ref)
+	deltaWithin(t, loc(), lat, 52.5, 2e-5)
+	deltaWithin(t, loc(), lon, 13.4, 2e-5)
+
+	for _, ref := range []string{
+		"", "18", "18S", "18SU", "61SUJ", "0SUJ", "123SUJ", "18IUJ", "18SUJ1",
+		"18SUJ12345678901", "18SUJ12x4", "18SIJ", "18SUW", "18NUD", "18NUU", "CAH", "ZZZ",
+	} {
+		if _, _, err := e.FromMGRS(ref); err == nil {
+			t.Errorf("FromMGRS(%q) did not fail", ref)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ellipsoid
+
+import "math"
+
+// Parameters are the derived constants of an ellipsoid. Lengths are in
+// the distance units of the Ellipsoid, the area in their square and the
+// volume in their cube.
+type Parameters struct {
+	Equatorial         float64 // semi-major axis a
+	Polar              float64 // semi-minor axis b
+	Flattening         float64 // f = (a - b) / a
+	InvFlattening      float64 // 1 / f, +Inf for a sphere
+	Eccentricity       float64 // e, negative for a prolate ellipsoid
+	SecondEccentricity float64 // e' = e / sqrt(1 - e^2)
+	ThirdFlattening    float64 // n = (a - b) / (a + b)
+	MeanRadius         float64 // R1 = (2a + b) / 3
+	AuthalicRadius     float64 // R2, the radius of the sphere of the same area
+	VolumetricRadius   float64 // R3, the radius of the sphere of the same volume
+	Area               float64 // the surface area
+	Volume             float64 // the volume
+}
+
+// polar returns the semi-minor axis b in meters.
+func (e ellipse) polar() float64 {
+	return e.Equatorial * (1 - e.flattening())
+}
+
+// secondEccentricitySquared returns e'^2 = (a^2 - b^2) / b^2.
+func (e ellipse) secondEccentricitySquared() float64 {
+	e2 := e.eccentricitySquared()
+	return e2 / (1 - e2)
+}
+
+// area returns the surface area of the ellipsoid in square meters.
+func (e ellipse) area() float64 {
+	a := e.Equatorial
+	return twopi * a * a * (1 - e.eccentricitySquared()) * e.authalicQ(pi/2)
+}
+
+/*
+Parameters returns the derived constants of the ellipsoid: the semi-minor
+axis, the eccentricities, the third flattening, the mean radii, the
+surface area and the volume.
+
+	p := geo.Parameters()
+	fmt.Println(p.Polar, p.Eccentricity, p.AuthalicRadius)
+*/
+func (ellipsoid Ellipsoid) Parameters() Parameters {
+	e := ellipsoid.Ellipse
+	k := ellipsoid.DistanceFactor
+	a, b := e.Equatorial/k, e.polar()/k
+	area := e.area() / (k * k)
+	return Parameters{
+		Equatorial:         a,
+		Polar:              b,
+		Flattening:         e.flattening(),
+		InvFlattening:      e.InvFlattening,
+		Eccentricity:       e.eccentricity(),
+		SecondEccentricity: math.Copysign(math.Sqrt(math.Abs(e.secondEccentricitySquared())), e.flattening()),
+		ThirdFlattening:    e.thirdFlattening(),
+		MeanRadius:         (2*a + b) / 3,
+		AuthalicRadius:     math.Sqrt(area / (4 * pi)),
+		VolumetricRadius:   math.Cbrt(a * a * b),
+		Area:               area,
+		Volume:             4 * pi / 3 * a * a * b,
+	}
+}
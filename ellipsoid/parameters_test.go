@@ -0,0 +1,39 @@
+package ellipsoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParameters(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+	p := e.Parameters()
+
+	deltaWithin(t, loc(), p.Equatorial, 6378137, 1e-9)
+	deltaWithin(t, loc(), p.Polar, 6356752.314245, 1e-6)
+	deltaWithin(t, loc(), p.InvFlattening, 298.257223563, 1e-9)
+	deltaWithin(t, loc(), sq(p.Eccentricity), 0.00669437999014, 1e-14)
+	deltaWithin(t, loc(), sq(p.SecondEccentricity), 0.00673949674228, 1e-14)
+	deltaWithin(t, loc(), p.ThirdFlattening, (p.Equatorial-p.Polar)/(p.Equatorial+p.Polar), 1e-16)
+	deltaWithin(t, loc(), p.MeanRadius, 6371008.7714, 1e-4)
+	deltaWithin(t, loc(), p.AuthalicRadius, 6371007.1809, 1e-4)
+	deltaWithin(t, loc(), p.VolumetricRadius, 6371000.7900, 1e-4)
+	deltaWithin(t, loc(), p.Area, 510065621724088.5, 1)
+	deltaWithin(t, loc(), p.Volume, 4*pi/3*math.Pow(p.VolumetricRadius, 3), 1e6)
+
+	// The area agrees with the one the polygons use.
+	deltaWithin(t, loc(), p.Area, 4*pi*newGeodesic(e.Ellipse).c2, 1e3)
+
+	k := Init("WGS84", Degrees, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric).Parameters()
+	deltaWithin(t, loc(), k.Polar, p.Polar/1000, 1e-12)
+	deltaWithin(t, loc(), k.Area, p.Area/1e6, 1e-3)
+	deltaWithin(t, loc(), k.Volume, p.Volume/1e9, 1)
+	deltaWithin(t, loc(), k.Eccentricity, p.Eccentricity, 1e-9)
+
+	s, _ := NewCustom(6371000, 0)
+	q := s.Parameters()
+	deltaWithin(t, loc(), q.Polar, 6371000, 1e-9)
+	deltaWithin(t, loc(), q.Eccentricity, 0, 1e-9)
+	deltaWithin(t, loc(), q.AuthalicRadius, 6371000, 1e-6)
+	deltaWithin(t, loc(), q.Area, 4*pi*6371000*6371000, 1)
+}
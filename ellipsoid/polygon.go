@@ -0,0 +1,192 @@
+package ellipsoid
+
+import "math"
+
+// Polygon accumulates the vertices of a geodesic polygon and computes
+// its perimeter and area. The edges are geodesics. Polygons may enclose
+// a pole and cross the antimeridian; the vertices are given in the
+// units of the Ellipsoid. This follows the polygon code of GeographicLib.
+type Polygon struct {
+	ellipsoid Ellipsoid
+	g         *geodesic
+
+	lat0, lon0 float64 // the first vertex in degrees
+	lat, lon   float64 // the last vertex in degrees
+	num        int     // the number of vertices
+	crossings  int     // the number of crossings of the prime meridian
+
+	perimeter, area accumulator // in meters and square meters
+}
+
+/*
+NewPolygon returns an empty Polygon on the ellipsoid.
+
+	p := geo.NewPolygon()
+	p.AddPoint(lat1, lon1)
+	p.AddPoint(lat2, lon2)
+	p.AddPoint(lat3, lon3)
+	perimeter, area := p.Compute()
+*/
+func (ellipsoid Ellipsoid) NewPolygon() *Polygon {
+	return &Polygon{ellipsoid: ellipsoid, g: cachedGeodesic(ellipsoid.Ellipse)}
+}
+
+/*
+PolygonArea returns the perimeter and the signed area of the polygon with
+the given vertices. See Polygon.Compute.
+
+	perimeter, area := geo.PolygonArea(locs)
+*/
+func (ellipsoid Ellipsoid) PolygonArea(locs []Location) (perimeter, area float64) {
+	p := ellipsoid.NewPolygon()
+	for _, l := range locs {
+		p.AddPoint(l.Lat, l.Lon)
+	}
+	return p.Compute()
+}
+
+// Clear removes all vertices from the polygon.
+func (p *Polygon) Clear() {
+	*p = Polygon{ellipsoid: p.ellipsoid, g: p.g}
+}
+
+// Num returns the number of vertices of the polygon.
+func (p *Polygon) Num() int {
+	return p.num
+}
+
+// AddPoint adds a vertex to the polygon.
+func (p *Polygon) AddPoint(lat, lon float64) {
+	if p.ellipsoid.Units == Radians {
+		lat = rad2deg(lat)
+		lon = rad2deg(lon)
+	}
+	if p.num == 0 {
+		p.lat0, p.lon0 = lat, lon
+	} else {
+		_, s12, _, _, _, _, _, _, _, S12 := p.g.genInverse(p.lat, p.lon, lat, lon, outDistance|outArea)
+		p.perimeter.add(s12)
+		p.area.add(S12)
+		p.crossings += transit(p.lon, lon)
+	}
+	p.lat, p.lon = lat, lon
+	p.num++
+}
+
+// AddEdge adds the vertex at the distance s in the direction azi from
+// the last vertex. It does nothing for an empty polygon.
+func (p *Polygon) AddEdge(azi, s float64) {
+	if p.num == 0 {
+		return
+	}
+	if p.ellipsoid.Units == Radians {
+		azi = rad2deg(azi)
+	}
+	s *= p.ellipsoid.DistanceFactor
+	l := p.g.line(p.lat, p.lon, azi, outLatitude|outLongitude|outDistanceIn|outArea)
+	_, lat, lon, _, _, _, _, _, S12 := l.genPosition(false, s,
+		outLatitude|outLongitude|outArea|outLongUnroll)
+	p.perimeter.add(s)
+	p.area.add(S12)
+	p.crossings += transitDirect(p.lon, lon)
+	p.lat, p.lon = lat, lon
+	p.num++
+}
+
+/*
+Compute returns the perimeter of the closed polygon in the distance units
+of the ellipsoid and its area in their square. The area is positive if
+the vertices are in counter-clockwise order and negative otherwise. A
+polygon with fewer than three vertices has no area.
+
+	perimeter, area := p.Compute()
+*/
+func (p *Polygon) Compute() (perimeter, area float64) {
+	if p.num < 2 {
+		return 0, 0
+	}
+	_, s12, _, _, _, _, _, _, _, S12 := p.g.genInverse(p.lat, p.lon, p.lat0, p.lon0, outDistance|outArea)
+	perimeter = p.perimeter.sum(s12)
+	a := p.area
+	a.add(S12)
+	area = a.reduce(4*pi*p.g.c2, p.crossings+transit(p.lon, p.lon0))
+
+	f := p.ellipsoid.DistanceFactor
+	return perimeter / f, area / (f * f)
+}
+
+// transit returns 1 or -1 if the edge from lon1 to lon2 crosses the
+// prime meridian going east or west, and 0 otherwise.
+func transit(lon1, lon2 float64) int {
+	// Compute lon12 the same way as genInverse.
+	lon12, _ := angDiff(lon1, lon2)
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+	switch {
+	case lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)):
+		return 1
+	case lon12 < 0 && lon1 >= 0 && lon2 < 0:
+		return -1
+	}
+	return 0
+}
+
+// transitDirect is transit for the unrolled longitudes of AddEdge.
+func transitDirect(lon1, lon2 float64) int {
+	// Compute exactly the parity of
+	// int(floor(lon2 / 360)) - int(floor(lon1 / 360))
+	lon1 = math.Remainder(lon1, 720)
+	lon2 = math.Remainder(lon2, 720)
+	t := 0
+	if !(lon2 >= 0 && lon2 < 360) {
+		t++
+	}
+	if !(lon1 >= 0 && lon1 < 360) {
+		t--
+	}
+	return t
+}
+
+// accumulator is a sum with twice the precision of a float64; s is the
+// sum and t the round-off error.
+type accumulator struct {
+	s, t float64
+}
+
+func (a *accumulator) add(y float64) {
+	z, u := sumx(y, a.t)
+	a.s, a.t = sumx(z, a.s)
+	if a.s == 0 {
+		a.s = u
+	} else {
+		a.t += u
+	}
+}
+
+// sum returns the sum of the accumulator and y.
+func (a accumulator) sum(y float64) float64 {
+	a.add(y)
+	return a.s
+}
+
+// reduce returns the accumulated area, which is in the clockwise sense,
+// reduced to (-area0/2, area0/2] in the counter-clockwise sense. area0
+// is the area of the ellipsoid.
+func (a accumulator) reduce(area0 float64, crossings int) float64 {
+	a.s = math.Remainder(a.s, area0)
+	a.add(0)
+	if crossings&1 != 0 {
+		if a.s < 0 {
+			a.add(area0 / 2)
+		} else {
+			a.add(-area0 / 2)
+		}
+	}
+	a.s, a.t = -a.s, -a.t
+	if a.s > area0/2 {
+		a.add(-area0)
+	} else if a.s <= -area0/2 {
+		a.add(area0)
+	}
+	return 0 + a.s
+}
@@ -0,0 +1,119 @@
+package ellipsoid
+
+import (
+	"math"
+	"testing"
+)
+
+type testobjectPolygon struct {
+	loc       string
+	points    [][2]float64
+	perimeter float64
+	area      float64
+}
+
+// The planimeter tests of GeographicLib.
+func TestPolygon(t *testing.T) {
+	e, err := New("WGS84")
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+
+	allTests := []testobjectPolygon{
+		{loc(), [][2]float64{{89, 0}, {89, 90}, {89, 180}, {89, 270}}, 631819.8745, 24952305678.0},
+		{loc(), [][2]float64{{-89, 0}, {-89, 90}, {-89, 180}, {-89, 270}}, 631819.8745, -24952305678.0},
+		{loc(), [][2]float64{{0, -1}, {-1, 0}, {0, 1}, {1, 0}}, 627598.2731, 24619419146.0},
+		{loc(), [][2]float64{{90, 0}, {0, 0}, {0, 90}}, 30022685, 63758202715511.0},
+		{loc(), [][2]float64{{89, 0.1}, {89, 90.1}, {89, -179.9}}, 539297, 12476152838.5},
+		{loc(), [][2]float64{{9, -0.00000000000001}, {9, 180}, {9, 0}}, 36026861, 0},
+		{loc(), [][2]float64{{9, 0.00000000000001}, {9, 0}, {9, 180}}, 36026861, 0},
+		{loc(), [][2]float64{{89, -360}, {89, -240}, {89, -120}, {89, 0}, {89, 120}, {89, 240}}, 1160741, 32415230256.0},
+	}
+	for _, v := range allTests {
+		p := e.NewPolygon()
+		for _, pt := range v.points {
+			p.AddPoint(pt[0], pt[1])
+		}
+		perimeter, area := p.Compute()
+		deltaWithin(t, v.loc, perimeter, v.perimeter, 1)
+		deltaWithin(t, v.loc, area, v.area, 1)
+		if p.Num() != len(v.points) {
+			t.Errorf("%s Num: got %d, want %d", v.loc, p.Num(), len(v.points))
+		}
+	}
+}
+
+func TestPolygonAntimeridian(t *testing.T) {
+	e := Init("WGS84", Degrees, Kilometer, LongitudeIsSymmetric, BearingIsSymmetric)
+
+	// A square across the antimeridian has the area of the same square
+	// across the prime meridian, clockwise it is negative.
+	square := []Location{{Lat: 0, Lon: -1}, {Lat: 0, Lon: 1}, {Lat: 1, Lon: 1}, {Lat: 1, Lon: -1}}
+	perimeter, area := e.PolygonArea(square)
+	if area <= 0 {
+		t.Errorf("PolygonArea: counter-clockwise area %v is not positive", area)
+	}
+	shifted := []Location{{Lat: 0, Lon: 179}, {Lat: 0, Lon: -179}, {Lat: 1, Lon: -179}, {Lat: 1, Lon: 179}}
+	p2, a2 := e.PolygonArea(shifted)
+	deltaWithin(t, loc(), p2, perimeter, 1e-9)
+	deltaWithin(t, loc(), a2, area, 1e-6)
+	reversed := []Location{shifted[3], shifted[2], shifted[1], shifted[0]}
+	p2, a2 = e.PolygonArea(reversed)
+	deltaWithin(t, loc(), p2, perimeter, 1e-9)
+	deltaWithin(t, loc(), a2, -area, 1e-6)
+
+	// The same square in radians and miles.
+	r := Init("WGS84", Radians, Mile, LongitudeNotSymmetric, BearingIsSymmetric)
+	for i := range shifted {
+		shifted[i].Lat *= degree
+		shifted[i].Lon *= degree
+	}
+	p2, a2 = r.PolygonArea(shifted)
+	deltaWithin(t, loc(), p2, perimeter*1000/1609.344, 1e-9)
+	deltaWithin(t, loc(), a2, area*1e6/(1609.344*1609.344), 1e-6)
+}
+
+func TestPolygonAddEdge(t *testing.T) {
+	e, err := New("WGS84")
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+
+	// Walk around the north pole along edges and compare with the
+	// polygon of the vertices.
+	p := e.NewPolygon()
+	q := e.NewPolygon()
+	p.AddPoint(89, 0)
+	q.AddPoint(89, 0)
+	lat, lon := 89.0, 0.0
+	for i := 0; i < 3; i++ {
+		line := e.InverseLine(lat, lon, 89, float64(90*(i+1)))
+		_, _, azi := line.Position(0)
+		p.AddEdge(azi, line.Distance())
+		lat, lon = 89, float64(90*(i+1))
+		q.AddPoint(lat, lon)
+	}
+	pp, pa := p.Compute()
+	qp, qa := q.Compute()
+	deltaWithin(t, loc(), pp, qp, 1e-6)
+	deltaWithin(t, loc(), pa, qa, 1)
+	deltaWithin(t, loc(), pa, 24952305678.0, 1)
+
+	// An edge needs a vertex to start from. Going north and then east
+	// is clockwise.
+	p.Clear()
+	p.AddEdge(90, 1000)
+	if p.Num() != 0 {
+		t.Errorf("AddEdge: added an edge to an empty polygon")
+	}
+	p.AddPoint(0, 0)
+	p.AddEdge(0, 1000e3)
+	p.AddEdge(90, 1000e3)
+	pp, pa = p.Compute()
+	if pa >= 0 || math.IsNaN(pa) {
+		t.Errorf("Compute: clockwise area %v is not negative", pa)
+	}
+	if p.Num() != 3 {
+		t.Errorf("Num: got %d, want 3", p.Num())
+	}
+}
@@ -0,0 +1,150 @@
+package ellipsoid
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// registry holds the ellipsoids that can be selected by name. The
+// keys of both maps are upper case.
+var registry = struct {
+	sync.RWMutex
+	ellipses map[string]ellipse
+	aliases  map[string]string // alias -> registered name
+}{
+	ellipses: map[string]ellipse{
+		"AIRY":                  {6377563.396, 299.3249646},
+		"AIRY-MODIFIED":         {6377340.189, 299.3249646},
+		"AUSTRALIAN":            {6378160.0, 298.25},
+		"BESSEL-1841":           {6377397.155, 299.1528128},
+		"BESSEL-1841-NAMIBIA":   {6377483.865, 299.152813},
+		"CLARKE-1866":           {6378206.400, 294.978698},
+		"CLARKE-1880":           {6378249.145, 293.465},
+		"EVEREST-1830":          {6377276.345, 300.8017},
+		"EVEREST-1948":          {6377304.063, 300.8017},
+		"EVEREST-SABAH-SARAWAK": {6377298.556, 300.801700},
+		"EVEREST-1956":          {6377301.243, 300.801700},
+		"EVEREST-1969":          {6377295.664, 300.801700},
+		"FISHER-1960":           {6378166.0, 298.3},
+		"FISHER-1960-MODIFIED":  {6378155.000, 298.300000},
+		"FISHER-1968":           {6378150.0, 298.3},
+		"GRS80":                 {6378137.0, 298.25722210088},
+		"HELMERT-1906":          {6378200.000, 298.300000},
+		"HOUGH-1956":            {6378270.0, 297.0},
+		"IAU76":                 {6378140.0, 298.257},
+		"INTERNATIONAL":         {6378388.000, 297.000000},
+		"KRASSOVSKY-1938":       {6378245.0, 298.3},
+		"NAD27":                 {6378206.4, 294.9786982138},
+		"NWL-9D":                {6378145.0, 298.25},
+		"SGS85":                 {6378136.000, 298.257000},
+		"SOUTHAMERICAN-1969":    {6378160.0, 298.25},
+		"SOVIET-1985":           {6378136.0, 298.257},
+		"WGS60":                 {6378165.000, 298.300000},
+		"WGS66":                 {6378145.000, 298.250000},
+		"WGS72":                 {6378135.0, 298.26},
+		"WGS84":                 {6378137.0, 298.257223563},
+	},
+	aliases: map[string]string{
+		"FISCHER-1960-MODIFIED": "FISHER-1960-MODIFIED", // old misspelling
+		"HAYFORD":               "INTERNATIONAL",
+		"EVEREST-MODIFIED":      "EVEREST-1948",
+	},
+}
+
+func canonical(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+// lookup resolves a name or an alias to its ellipse.
+func lookup(name string) (ellipse, bool) {
+	name = canonical(name)
+	registry.RLock()
+	defer registry.RUnlock()
+	if target, ok := registry.aliases[name]; ok {
+		name = target
+	}
+	e, ok := registry.ellipses[name]
+	return e, ok
+}
+
+/*
+Lookup returns the semi-major axis in meters and the inverse flattening
+of a registered ellipsoid. The name is not case sensitive and may be an
+alias. The inverse flattening of a sphere is +Inf.
+
+	a, invf, ok := ellipsoid.Lookup("wgs84")
+*/
+func Lookup(name string) (equatorial, invFlattening float64, ok bool) {
+	e, ok := lookup(name)
+	return e.Equatorial, e.InvFlattening, ok
+}
+
+// Names returns the sorted names of all registered ellipsoids,
+// without the aliases.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.ellipses))
+	for name := range registry.ellipses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+Register adds an ellipsoid to the registry, so that it can be selected
+by name in Init and New. The parameters are the same as for NewCustom.
+The name is stored in upper case and must not be in use already.
+Register is meant to be called once at startup, but it is safe to call
+concurrently with lookups.
+
+	err := ellipsoid.Register("CGCS2000", 6378137.0, 298.257222101)
+*/
+func Register(name string, equatorial, invFlattening float64) error {
+	e, err := newEllipse(equatorial, invFlattening)
+	if err != nil {
+		return err
+	}
+	name = canonical(name)
+	if name == "" {
+		return &InvalidNameError{Name: name}
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if registered(name) {
+		return &DuplicateEllipsoidError{Name: name}
+	}
+	registry.ellipses[name] = e
+	return nil
+}
+
+// RegisterAlias adds an alternative name for a registered ellipsoid.
+func RegisterAlias(alias, name string) error {
+	alias, name = canonical(alias), canonical(name)
+	registry.Lock()
+	defer registry.Unlock()
+	if target, ok := registry.aliases[name]; ok {
+		name = target
+	}
+	if _, ok := registry.ellipses[name]; !ok {
+		return &UnknownEllipsoidError{Name: name}
+	}
+	if alias == "" {
+		return &InvalidNameError{Name: alias}
+	}
+	if registered(alias) {
+		return &DuplicateEllipsoidError{Name: alias}
+	}
+	registry.aliases[alias] = name
+	return nil
+}
+
+// registered reports whether name is in use. The caller must hold the
+// registry lock.
+func registered(name string) bool {
+	_, isEllipse := registry.ellipses[name]
+	_, isAlias := registry.aliases[name]
+	return isEllipse || isAlias
+}
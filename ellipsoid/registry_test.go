@@ -0,0 +1,127 @@
+package ellipsoid
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	a, invf, ok := Lookup("wgs84")
+	if !ok || a != 6378137.0 || invf != 298.257223563 {
+		t.Errorf("Lookup(wgs84) = %v, %v, %v", a, invf, ok)
+	}
+	for _, v := range [][2]string{
+		{"HAYFORD", "INTERNATIONAL"},
+		{"FISCHER-1960-MODIFIED", "FISHER-1960-MODIFIED"},
+		{"Everest-Modified", "EVEREST-1948"},
+	} {
+		a1, f1, ok1 := Lookup(v[0])
+		a2, f2, ok2 := Lookup(v[1])
+		if !ok1 || !ok2 || a1 != a2 || f1 != f2 {
+			t.Errorf("Lookup: alias %v does not match %v", v[0], v[1])
+		}
+	}
+	if _, _, ok := Lookup("NO-SUCH-ELLIPSOID"); ok {
+		t.Errorf("Lookup: found unknown ellipsoid")
+	}
+
+	// Init still accepts every name it accepted before.
+	e := Init("FISCHER-1960-MODIFIED", Degrees, Meter, LongitudeIsSymmetric, BearingIsSymmetric)
+	if e.Ellipse.Equatorial != 6378155.0 {
+		t.Errorf("Init: misspelt alias not resolved: %+v", e)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Names: not sorted: %v", names)
+	}
+	for _, name := range names {
+		if _, err := New(name); err != nil {
+			t.Errorf("Names: %v cannot be used with New: %v", name, err)
+		}
+		if name == "HAYFORD" {
+			t.Errorf("Names: contains alias %v", name)
+		}
+	}
+}
+
+// unregister removes names from the registry, so that the tests that
+// register ellipsoids can be repeated.
+func unregister(t *testing.T, names ...string) {
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		for _, name := range names {
+			delete(registry.ellipses, canonical(name))
+			delete(registry.aliases, canonical(name))
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	unregister(t, "TEST-CGCS2000", "TEST-CHINA-2000", "TEST-INVALID", "TEST-ALIAS")
+	if err := Register("test-cgcs2000", 6378137.0, 298.257222101); err != nil {
+		t.Fatalf("Register: unexpected error %v", err)
+	}
+	e, err := New("TEST-CGCS2000")
+	if err != nil {
+		t.Fatalf("New: unexpected error %v", err)
+	}
+	if e.Ellipse.InvFlattening != 298.257222101 {
+		t.Errorf("New: registered ellipsoid not used: %+v", e)
+	}
+	if err := RegisterAlias("test-china-2000", "test-cgcs2000"); err != nil {
+		t.Fatalf("RegisterAlias: unexpected error %v", err)
+	}
+	if _, _, ok := Lookup("TEST-CHINA-2000"); !ok {
+		t.Errorf("Lookup: registered alias not found")
+	}
+
+	var duplicate *DuplicateEllipsoidError
+	if err := Register("WGS84", 6378137.0, 298.0); !errors.As(err, &duplicate) {
+		t.Errorf("Register: expected DuplicateEllipsoidError, got %v", err)
+	}
+	if err := Register("HAYFORD", 6378137.0, 298.0); !errors.As(err, &duplicate) {
+		t.Errorf("Register: expected DuplicateEllipsoidError, got %v", err)
+	}
+	var invalid *InvalidEllipseError
+	if err := Register("TEST-INVALID", 0, 298.0); !errors.As(err, &invalid) {
+		t.Errorf("Register: expected InvalidEllipseError, got %v", err)
+	}
+	var name *InvalidNameError
+	if err := Register("  ", 6378137.0, 298.0); !errors.As(err, &name) {
+		t.Errorf("Register: expected InvalidNameError, got %v", err)
+	}
+	if err := RegisterAlias("", "WGS84"); !errors.As(err, &name) {
+		t.Errorf("RegisterAlias: expected InvalidNameError, got %v", err)
+	}
+	var unknown *UnknownEllipsoidError
+	if err := RegisterAlias("TEST-ALIAS", "NO-SUCH-ELLIPSOID"); !errors.As(err, &unknown) {
+		t.Errorf("RegisterAlias: expected UnknownEllipsoidError, got %v", err)
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		unregister(t, "TEST-SPHERE-"+string(rune('A'+i)))
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			Register("TEST-SPHERE-"+string(rune('A'+i)), 6371000.0, 0)
+		}(i)
+		go func() {
+			defer wg.Done()
+			Names()
+			Lookup("WGS84")
+		}()
+	}
+	wg.Wait()
+	if _, _, ok := Lookup("TEST-SPHERE-H"); !ok {
+		t.Errorf("Register: concurrent registration lost")
+	}
+}
@@ -0,0 +1,176 @@
+package ellipsoid
+
+// Rhumb lines (loxodromes) on the ellipsoid. A rhumb line crosses all
+// meridians at the same angle; it is a straight line on the Mercator
+// projection. Along it the isometric latitude changes in proportion to
+// the longitude, and the distance in proportion to the meridian arc.
+
+import "math"
+
+// isometricLatitude returns the isometric latitude psi of the latitude
+// phi in radians. It is infinite at the poles.
+func (e ellipse) isometricLatitude(phi float64) float64 {
+	if math.Abs(phi) >= pi/2 {
+		return math.Copysign(math.Inf(1), phi)
+	}
+	ecc := math.Sqrt(e.eccentricitySquared())
+	return math.Asinh(math.Tan(phi)) - ecc*math.Atanh(ecc*math.Sin(phi))
+}
+
+func (e ellipse) flattening() float64 {
+	return 1 / e.InvFlattening
+}
+
+func (e ellipse) eccentricitySquared() float64 {
+	f := e.flattening()
+	return f * (2 - f)
+}
+
+// thirdFlattening returns n = (a - b) / (a + b).
+func (e ellipse) thirdFlattening() float64 {
+	f := e.flattening()
+	return f / (2 - f)
+}
+
+// meridianArc returns the distance in meters along the meridian from the
+// equator to the latitude phi in radians. It uses Helmert's series in
+// the third flattening n to fourth order.
+func (e ellipse) meridianArc(phi float64) float64 {
+	n := e.thirdFlattening()
+	n2 := n * n
+	return e.Equatorial / (1 + n) * ((1+n2/4+n2*n2/64)*phi -
+		3.0/2*(n-n2*n/8)*math.Sin(2*phi) +
+		15.0/16*(n2-n2*n2/4)*math.Sin(4*phi) -
+		35.0/48*n2*n*math.Sin(6*phi) +
+		315.0/512*n2*n2*math.Sin(8*phi))
+}
+
+// footpointLatitude is the inverse of meridianArc; it returns the
+// latitude in radians for the meridian distance m in meters.
+func (e ellipse) footpointLatitude(m float64) float64 {
+	n := e.thirdFlattening()
+	n2 := n * n
+	mu := m / (e.Equatorial / (1 + n) * (1 + n2/4 + n2*n2/64))
+	return mu + (3.0/2*n-27.0/32*n2*n)*math.Sin(2*mu) +
+		(21.0/16*n2-55.0/32*n2*n2)*math.Sin(4*mu) +
+		151.0/96*n2*n*math.Sin(6*mu) +
+		1097.0/512*n2*n2*math.Sin(8*mu)
+}
+
+// rhumbScale returns dm/dpsi between the latitudes phi1 and phi2 in
+// radians, the radius of the parallel for nearly equal latitudes.
+func (e ellipse) rhumbScale(phi1, phi2 float64) float64 {
+	if math.Abs(phi2-phi1) < 1e-6 {
+		phi := (phi1 + phi2) / 2
+		return e.Equatorial * math.Cos(phi) / math.Sqrt(1-e.eccentricitySquared()*sq(math.Sin(phi)))
+	}
+	return (e.meridianArc(phi2) - e.meridianArc(phi1)) /
+		(e.isometricLatitude(phi2) - e.isometricLatitude(phi1))
+}
+
+// rhumbInverse returns the length in meters and the course in radians
+// of the shorter rhumb line between two points in radians.
+func (e ellipse) rhumbInverse(phi1, lam1, phi2, lam2 float64) (s12, azi12 float64) {
+	lam12 := math.Remainder(lam2-lam1, twopi)
+	m12 := e.meridianArc(phi2) - e.meridianArc(phi1)
+	psi12 := e.isometricLatitude(phi2) - e.isometricLatitude(phi1)
+	if phi1 == phi2 {
+		psi12 = 0
+	}
+	azi12 = math.Atan2(lam12, psi12)
+	s12 = math.Hypot(m12, lam12*e.rhumbScale(phi1, phi2))
+	return s12, azi12
+}
+
+// rhumbDirect returns the point in radians at the distance s12 in meters
+// along the rhumb line from a point with the course azi12 in radians.
+// Beyond the pole the point is NaN.
+func (e ellipse) rhumbDirect(phi1, lam1, s12, azi12 float64) (phi2, lam2 float64) {
+	m2 := e.meridianArc(phi1) + s12*math.Cos(azi12)
+	if math.Abs(m2) > e.meridianArc(pi/2) {
+		return math.NaN(), math.NaN()
+	}
+	phi2 = e.footpointLatitude(m2)
+	lam2 = lam1
+	// At a pole the longitude is undefined and the scale vanishes, so
+	// the longitude of the start is kept.
+	if dlam := s12 * math.Sin(azi12); dlam != 0 {
+		if scale := e.rhumbScale(phi1, phi2); scale != 0 && !math.IsNaN(scale) {
+			lam2 += dlam / scale
+		}
+	}
+	return phi2, lam2
+}
+
+/*
+RhumbTo returns range and constant bearing of the rhumb line between two
+specified locations. Of the two rhumb lines around the earth the shorter
+one is chosen.
+
+	dist, theta  = geo.RhumbTo( lat1, lon1, lat2, lon2 )
+*/
+func (ellipsoid Ellipsoid) RhumbTo(lat1, lon1, lat2, lon2 float64) (distance, bearing float64) {
+	if ellipsoid.Units == Degrees {
+		lat1 = deg2rad(lat1)
+		lon1 = deg2rad(lon1)
+		lat2 = deg2rad(lat2)
+		lon2 = deg2rad(lon2)
+	}
+
+	distance, bearing = ellipsoid.Ellipse.rhumbInverse(lat1, lon1, lat2, lon2)
+
+	bearing = ellipsoid.adjustBearing(bearing, pi)
+	if ellipsoid.Units == Degrees {
+		bearing = rad2deg(bearing)
+	}
+	distance /= ellipsoid.DistanceFactor
+	return
+}
+
+/*
+RhumbAt returns the list latitude,longitude in degrees or radians that is
+a specified range along the rhumb line with a constant bearing from a
+given location. The result is NaN if the rhumb line reaches a pole before
+the range.
+
+	lat2, lon2  = geo.RhumbAt( lat1, lon1, range, bearing )
+*/
+func (ellipsoid Ellipsoid) RhumbAt(lat1, lon1, distance, bearing float64) (lat2, lon2 float64) {
+	if ellipsoid.Units == Degrees {
+		lat1 = deg2rad(lat1)
+		lon1 = deg2rad(lon1)
+		bearing = deg2rad(bearing)
+	}
+
+	lat2, lon2 = ellipsoid.Ellipse.rhumbDirect(lat1, lon1, distance*ellipsoid.DistanceFactor, bearing)
+
+	lon2 = ellipsoid.adjustLongitude(math.Remainder(lon2, twopi), pi)
+	if ellipsoid.Units == Degrees {
+		lat2 = rad2deg(lat2)
+		lon2 = rad2deg(lon2)
+	}
+	return
+}
+
+/*
+RhumbIntermediate is Intermediate for the rhumb line between two
+coordinates. It returns range and bearing as RhumbTo and an array with
+the lats and lons of steps+1 points on the rhumb line, INCLUDING the
+start and the endpoint.
+
+	dist, theta, arr = geo.RhumbIntermediate( lat1, lon1, lat2, lon2, steps )
+
+steps shall not be 0.
+*/
+func (ellipsoid Ellipsoid) RhumbIntermediate(lat1, lon1, lat2, lon2 float64, steps int) (distance, bearing float64, arr []float64) {
+	if steps <= 0 {
+		return
+	}
+	r, phi := ellipsoid.RhumbTo(lat1, lon1, lat2, lon2)
+	v := make([]float64, steps*2+2)
+	for i := 0; i <= steps; i++ {
+		a, b := ellipsoid.RhumbAt(lat1, lon1, r*float64(i)/float64(steps), phi)
+		v[i*2], v[i*2+1] = a, b
+	}
+	return r, phi, v
+}
@@ -0,0 +1,103 @@
+package ellipsoid
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRhumbTo(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	// JFK to LHR, from the documentation of GeographicLib's RhumbSolve.
+	dist, bear := e.RhumbTo(40.6, -73.8, 51.6, -0.5)
+	deltaWithin(t, loc(), dist, 5771083.383, 1e-3)
+	deltaWithin(t, loc(), bear, 77.768389710, 1e-9)
+
+	// Along a meridian the rhumb line is the geodesic.
+	k, _ := New("WGS84", WithGeodesicSolver(Karney))
+	want, _ := k.To(-30, 10, 60, 10)
+	dist, bear = e.RhumbTo(-30, 10, 60, 10)
+	deltaWithin(t, loc(), dist, want, 1e-6)
+	deltaWithin(t, loc(), bear, 0, 1e-12)
+	want, _ = k.To(0, 10, 90, 10)
+	dist, _ = e.RhumbTo(0, 10, 90, 20)
+	deltaWithin(t, loc(), dist, want, 1e-6)
+
+	// Along the equator it is the circle of radius a, the shorter way
+	// across the antimeridian.
+	dist, bear = e.RhumbTo(0, 170, 0, -170)
+	deltaWithin(t, loc(), dist, 20*degree*e.Ellipse.Equatorial, 1e-6)
+	deltaWithin(t, loc(), bear, 90, 1e-12)
+
+	r := Init("WGS84", Radians, Nm, LongitudeIsSymmetric, BearingIsSymmetric)
+	dist, bear = r.RhumbTo(51.6*degree, -0.5*degree, 40.6*degree, -73.8*degree)
+	deltaWithin(t, loc(), dist, 5771083.383/1852, 1e-6)
+	deltaWithin(t, loc(), bear, (77.768389710-180)*degree, 1e-9)
+}
+
+func TestRhumbAt(t *testing.T) {
+	e := Init("WGS84", Degrees, Kilometer, LongitudeNotSymmetric, BearingIsSymmetric)
+
+	lat2, lon2 := e.RhumbAt(40.6, -73.8, 5771.083383, 77.768389710)
+	deltaWithin(t, loc(), lat2, 51.6, 1e-8)
+	deltaWithin(t, loc(), lon2, 359.5, 1e-8)
+
+	// Around the world along a parallel.
+	lat2, lon2 = e.RhumbAt(30, 10, 2.5*twopi*e.Ellipse.rhumbScale(30*degree, 30*degree)/1000, 90)
+	deltaWithin(t, loc(), lat2, 30, 1e-9)
+	deltaWithin(t, loc(), lon2, 190, 1e-9)
+
+	// Into the pole and beyond.
+	dist, bear := e.RhumbTo(10, 0, 90, 0)
+	lat2, _ = e.RhumbAt(10, 0, dist, bear)
+	deltaWithin(t, loc(), lat2, 90, 1e-9)
+	lat2, lon2 = e.RhumbAt(10, 0, dist+1, bear)
+	if !math.IsNaN(lat2) || !math.IsNaN(lon2) {
+		t.Errorf("RhumbAt: got %v, %v beyond the pole", lat2, lon2)
+	}
+
+	// From a pole.
+	for _, c := range []struct{ lat, bear, want float64 }{
+		{90, 180, 89.991047},
+		{90, 179.999, 89.991047},
+		{-90, 0, -89.991047},
+		{-90, 0.001, -89.991047},
+	} {
+		lat2, lon2 = e.RhumbAt(c.lat, 20, 1, c.bear)
+		deltaWithin(t, loc(), lat2, c.want, 1e-5)
+		deltaWithin(t, loc(), lon2, 20, 1e-9)
+	}
+
+	r := Init("WGS84", Radians, Mile, LongitudeIsSymmetric, BearingIsSymmetric)
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < 1000; i++ {
+		lat1 := (rnd.Float64()*170 - 85) * degree
+		lon1 := (rnd.Float64()*360 - 180) * degree
+		lat2 := (rnd.Float64()*170 - 85) * degree
+		lon2 := (rnd.Float64()*360 - 180) * degree
+		dist, bear := r.RhumbTo(lat1, lon1, lat2, lon2)
+		la, lo := r.RhumbAt(lat1, lon1, dist, bear)
+		deltaWithin(t, loc(), la, lat2, 1e-12)
+		deltaWithin(t, loc(), math.Remainder(lo-lon2, twopi), 0, 1e-12)
+	}
+}
+
+func TestRhumbIntermediate(t *testing.T) {
+	e := Init("WGS84", Degrees, Kilometer, LongitudeIsSymmetric, BearingIsSymmetric)
+
+	dist, bear, arr := e.RhumbIntermediate(40.6, -73.8, 51.6, -0.5, 4)
+	if len(arr) != 10 {
+		t.Fatalf("RhumbIntermediate: got %d values, want 10", len(arr))
+	}
+	deltaWithin(t, loc(), arr[8], 51.6, 1e-8)
+	deltaWithin(t, loc(), arr[9], -0.5, 1e-8)
+	for i := 1; i <= 4; i++ {
+		d, b := e.RhumbTo(arr[2*i-2], arr[2*i-1], arr[2*i], arr[2*i+1])
+		deltaWithin(t, loc(), d, dist/4, 1e-6)
+		deltaWithin(t, loc(), b, bear, 1e-9)
+	}
+	if _, _, arr := e.RhumbIntermediate(40.6, -73.8, 51.6, -0.5, 0); arr != nil {
+		t.Errorf("RhumbIntermediate: got %v for 0 steps", arr)
+	}
+}
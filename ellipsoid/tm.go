@@ -0,0 +1,208 @@
+package ellipsoid
+
+// The transverse Mercator projection with Krüger's series to sixth order
+// in the third flattening n, following C. F. F. Karney, Transverse
+// Mercator with an accuracy of a few nanometers, J. Geodesy 85, 475-485
+// (2011). Within 3900 km of the central meridian the error is less than
+// 5 nm; the convergence and the scale come from the derivative of the
+// series.
+
+import (
+	"math"
+	"math/cmplx"
+	"sync"
+)
+
+// transverseMercator holds the series coefficients of an ellipse for
+// the central scale k0.
+type transverseMercator struct {
+	k0      float64
+	a1      float64    // the rectifying radius A in meters
+	b1      float64    // A / a
+	es      float64    // the signed eccentricity
+	e2, e2m float64    // e^2 and 1 - e^2
+	c       float64    // the scale at the pole, divided by k0
+	alp     [7]float64 // the coefficients of the forward series
+	bet     [7]float64 // the coefficients of the reverse series, negated
+}
+
+// transverseMercators caches the series of each ellipse and central
+// scale in use, like geodesics. A transverseMercator is not modified
+// after newTransverseMercator returns it.
+var transverseMercators = struct {
+	sync.RWMutex
+	m map[tmKey]*transverseMercator
+}{m: map[tmKey]*transverseMercator{}}
+
+type tmKey struct {
+	e  ellipse
+	k0 float64
+}
+
+// cachedTransverseMercator returns the transverse Mercator series of e
+// for the central scale k0, computing it on first use.
+func cachedTransverseMercator(e ellipse, k0 float64) *transverseMercator {
+	key := tmKey{e, k0}
+	transverseMercators.RLock()
+	tm, ok := transverseMercators.m[key]
+	transverseMercators.RUnlock()
+	if ok {
+		return tm
+	}
+	tm = newTransverseMercator(e, k0)
+	transverseMercators.Lock()
+	defer transverseMercators.Unlock()
+	if len(transverseMercators.m) < maxCachedEllipses {
+		transverseMercators.m[key] = tm
+	}
+	return tm
+}
+
+func newTransverseMercator(e ellipse, k0 float64) *transverseMercator {
+	n := e.thirdFlattening()
+	n2 := n * n
+	n3 := n2 * n
+	n4 := n3 * n
+	n5 := n4 * n
+	n6 := n5 * n
+	tm := &transverseMercator{
+		k0:  k0,
+		b1:  (1 + n2/4 + n4/64 + n6/256) / (1 + n),
+		es:  e.eccentricity(),
+		e2:  e.eccentricitySquared(),
+		e2m: 1 - e.eccentricitySquared(),
+	}
+	tm.a1 = tm.b1 * e.Equatorial
+	tm.c = math.Sqrt(tm.e2m) * math.Exp(eatanhe(1, tm.es))
+	tm.alp = [7]float64{0,
+		n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+		13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+		61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+		49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+		34729*n5/80640 - 3418889*n6/1995840,
+		212378941 * n6 / 319334400,
+	}
+	tm.bet = [7]float64{0,
+		-(n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800),
+		-(n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720),
+		-(17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720),
+		-(4397*n4/161280 - 11*n5/504 - 830251*n6/7257600),
+		-(4583*n5/161280 - 108847*n6/3991680),
+		-20648693 * n6 / 638668800,
+	}
+	return tm
+}
+
+// krueger returns zeta + sum c[j] sin(2 j zeta) and its derivative with
+// respect to zeta.
+func krueger(c *[7]float64, zeta complex128) (z, dz complex128) {
+	z, dz = zeta, 1
+	for j := 1; j < len(c); j++ {
+		t := complex(float64(2*j), 0) * zeta
+		z += complex(c[j], 0) * cmplx.Sin(t)
+		dz += complex(float64(2*j)*c[j], 0) * cmplx.Cos(t)
+	}
+	return z, dz
+}
+
+// forward returns the easting x and northing y in meters relative to the
+// central meridian lam0 and the equator, the convergence gamma and the
+// scale k of the point phi, lam. The angles are in radians.
+func (tm *transverseMercator) forward(lam0, phi, lam float64) (x, y, gamma, k float64) {
+	lam = math.Remainder(lam-lam0, twopi)
+	// Reduce to the first quadrant of the sheet; the projection is
+	// symmetric in the equator and the central meridian.
+	latsign, lonsign := 1.0, 1.0
+	if math.Signbit(phi) {
+		latsign = -1
+	}
+	if math.Signbit(lam) {
+		lonsign = -1
+	}
+	phi *= latsign
+	lam *= lonsign
+	backside := lam > pi/2
+	if backside {
+		if phi == 0 {
+			latsign = -1
+		}
+		lam = pi - lam
+	}
+
+	var xip, etap float64
+	slam, clam := math.Sin(lam), math.Cos(lam)
+	if phi != pi/2 {
+		tau := math.Tan(phi)
+		taup := taupf(tau, tm.es)
+		xip = math.Atan2(taup, clam)
+		etap = math.Asinh(slam / math.Hypot(taup, clam))
+		gamma = math.Atan2(slam*taup, clam*math.Hypot(1, taup))
+		k = math.Sqrt(tm.e2m+tm.e2*sq(math.Cos(phi))) * math.Hypot(1, tau) / math.Hypot(taup, clam)
+	} else {
+		xip = pi / 2
+		gamma = lam
+		k = tm.c
+	}
+
+	z, dz := krueger(&tm.alp, complex(xip, etap))
+	gamma -= math.Atan2(imag(dz), real(dz))
+	k *= tm.b1 * cmplx.Abs(dz)
+	xi, eta := real(z), imag(z)
+	if backside {
+		xi = pi - xi
+		gamma = pi - gamma
+	}
+	y = tm.a1 * tm.k0 * xi * latsign
+	x = tm.a1 * tm.k0 * eta * lonsign
+	gamma = math.Remainder(gamma*latsign*lonsign, twopi)
+	return x, y, gamma, k * tm.k0
+}
+
+// reverse is the inverse of forward. It returns the latitude phi and the
+// longitude lam in radians, the convergence gamma and the scale k at the
+// point x, y relative to the central meridian lam0.
+func (tm *transverseMercator) reverse(lam0, x, y float64) (phi, lam, gamma, k float64) {
+	xi := y / (tm.a1 * tm.k0)
+	eta := x / (tm.a1 * tm.k0)
+	xisign, etasign := 1.0, 1.0
+	if math.Signbit(xi) {
+		xisign = -1
+	}
+	if math.Signbit(eta) {
+		etasign = -1
+	}
+	xi *= xisign
+	eta *= etasign
+	backside := xi > pi/2
+	if backside {
+		xi = pi - xi
+	}
+
+	z, dz := krueger(&tm.bet, complex(xi, eta))
+	gamma = math.Atan2(imag(dz), real(dz))
+	k = tm.b1 / cmplx.Abs(dz)
+
+	xip, etap := real(z), imag(z)
+	s := math.Sinh(etap)
+	c := math.Max(0, math.Cos(xip))
+	if r := math.Hypot(s, c); r != 0 {
+		lam = math.Atan2(s, c)
+		sxip := math.Sin(xip)
+		tau := tauf(sxip/r, tm.es)
+		gamma += math.Atan2(sxip*math.Tanh(etap), c)
+		phi = math.Atan(tau)
+		k *= math.Sqrt(tm.e2m+tm.e2/(1+sq(tau))) * math.Hypot(1, tau) * r
+	} else {
+		phi = pi / 2
+		lam = 0
+		k *= tm.c
+	}
+	phi *= xisign
+	if backside {
+		lam = pi - lam
+		gamma = pi - gamma
+	}
+	lam = math.Remainder(lam*etasign+lam0, twopi)
+	gamma = math.Remainder(gamma*xisign*etasign, twopi)
+	return phi, lam, gamma, k * tm.k0
+}
@@ -0,0 +1,61 @@
+package ellipsoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransverseMercatorSeries(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+	k, _ := New("WGS84", WithGeodesicSolver(Karney))
+	tm := newTransverseMercator(e.Ellipse, 1)
+
+	// The central meridian is true to scale.
+	for lat := -90.0; lat <= 90; lat += 15 {
+		x, y, gamma, scale := tm.forward(0, lat*degree, 0)
+		deltaWithin(t, loc(), x, 0, 1e-9)
+		deltaWithin(t, loc(), y, e.MeridianArc(lat), 1e-6)
+		deltaWithin(t, loc(), gamma, 0, 1e-15)
+		deltaWithin(t, loc(), scale, 1, 1e-15)
+	}
+
+	// The convergence and the scale agree with a short geodesic, also 30
+	// degrees from the central meridian and on the back side of the sheet.
+	for _, p := range [][2]float64{{33.3, 44.4}, {-50, 10}, {60, -35}, {5, 30}, {-70, 100}, {89, 170}} {
+		x1, y1, gamma, scale := tm.forward(0, p[0]*degree, p[1]*degree)
+		for _, azi := range []float64{0, 60, 135} {
+			lat2, lon2 := k.At(p[0], p[1], 1, azi)
+			x2, y2, _, _ := tm.forward(0, lat2*degree, lon2*degree)
+			deltaWithin(t, loc(), math.Hypot(x2-x1, y2-y1), scale, 1e-6*scale)
+			grid := rad2deg(math.Atan2(x2-x1, y2-y1))
+			deltaWithin(t, loc(), math.Remainder(azi-grid-rad2deg(gamma), 360), 0, 1e-5)
+		}
+
+		lat, lon, gamma2, scale2 := tm.reverse(0, x1, y1)
+		deltaWithin(t, loc(), rad2deg(lat), p[0], 1e-12)
+		deltaWithin(t, loc(), rad2deg(lon), p[1], 1e-11)
+		deltaWithin(t, loc(), gamma2, gamma, 1e-12)
+		deltaWithin(t, loc(), scale2, scale, 1e-12)
+	}
+
+	// The poles.
+	x, y, _, _ := tm.forward(0, pi/2, 1)
+	deltaWithin(t, loc(), x, 0, 1e-9)
+	deltaWithin(t, loc(), y, e.MeridianArc(90), 1e-6)
+	lat, _, _, _ := tm.reverse(0, 0, e.MeridianArc(-90))
+	deltaWithin(t, loc(), rad2deg(lat), -90, 1e-12)
+}
+
+func TestCachedTransverseMercator(t *testing.T) {
+	e, _ := New("GRS80")
+	tm := cachedTransverseMercator(e.Ellipse, utmK0)
+	if cachedTransverseMercator(e.Ellipse, utmK0) != tm {
+		t.Errorf("cachedTransverseMercator: series not reused")
+	}
+	if cachedTransverseMercator(e.Ellipse, 1) == tm {
+		t.Errorf("cachedTransverseMercator: series shared between scales")
+	}
+	if *tm != *newTransverseMercator(e.Ellipse, utmK0) {
+		t.Errorf("cachedTransverseMercator: cached coefficients differ")
+	}
+}
@@ -0,0 +1,79 @@
+package ellipsoid
+
+import "math"
+
+// TransverseMercator is a transverse Mercator grid with an arbitrary
+// central meridian, latitude of origin, central scale and false origin,
+// e.g. a Gauss-Krüger zone or the British National Grid. It uses
+// Krüger's series to sixth order, which is accurate to a few nanometers
+// within 3900 km of the central meridian. Easting and northing are in
+// the distance units, angles in the units of the Ellipsoid.
+type TransverseMercator struct {
+	ellipsoid  Ellipsoid
+	lat0, lon0 float64 // radians
+	fe, fn     float64 // meters
+	y0         float64 // the northing of the origin in meters
+	tm         *transverseMercator
+}
+
+/*
+NewTransverseMercator returns the transverse Mercator grid with the
+latitude of origin lat0, the central meridian lon0, the scale k0 on the
+central meridian and the false easting and northing of the origin.
+
+	bng, err := airy.NewTransverseMercator(49, -2, 0.9996012717, 400000, -100000)
+	easting, northing, convergence, scale := bng.Forward(lat, lon)
+	lat, lon, convergence, scale = bng.Reverse(easting, northing)
+
+The error is an *OutOfRangeError if k0 is not a positive number or lat0
+is not a latitude.
+*/
+func (ellipsoid Ellipsoid) NewTransverseMercator(lat0, lon0, k0, falseEasting, falseNorthing float64) (TransverseMercator, error) {
+	if !(k0 > 0) || math.IsInf(k0, 1) {
+		return TransverseMercator{}, &OutOfRangeError{Param: "scale factor", Value: k0}
+	}
+	if !(math.Abs(ellipsoid.angleIn(lat0)) <= pi/2) {
+		return TransverseMercator{}, &OutOfRangeError{Param: "latitude of origin", Value: lat0}
+	}
+	p := TransverseMercator{
+		ellipsoid: ellipsoid,
+		lat0:      ellipsoid.angleIn(lat0),
+		lon0:      ellipsoid.angleIn(lon0),
+		fe:        falseEasting * ellipsoid.DistanceFactor,
+		fn:        falseNorthing * ellipsoid.DistanceFactor,
+		tm:        newTransverseMercator(ellipsoid.Ellipse, k0),
+	}
+	_, p.y0, _, _ = p.tm.forward(0, p.lat0, 0)
+	return p, nil
+}
+
+// Origin returns the latitude of origin and the central meridian.
+func (p TransverseMercator) Origin() (lat0, lon0 float64) {
+	return p.ellipsoid.angleOut(p.lat0), p.ellipsoid.angleOut(p.lon0)
+}
+
+/*
+Forward returns the easting and northing of lat, lon, the convergence,
+which is the angle from true north to grid north, and the point scale.
+
+	easting, northing, convergence, scale := p.Forward(lat, lon)
+*/
+func (p TransverseMercator) Forward(lat, lon float64) (easting, northing, convergence, scale float64) {
+	e := p.ellipsoid
+	x, y, gamma, k := p.tm.forward(p.lon0, e.angleIn(lat), e.angleIn(lon))
+	f := e.DistanceFactor
+	return (x + p.fe) / f, (y - p.y0 + p.fn) / f, e.angleOut(gamma), k
+}
+
+/*
+Reverse is the inverse of Forward. It returns the latitude and longitude
+of easting, northing with the convergence and the point scale there.
+
+	lat, lon, convergence, scale := p.Reverse(easting, northing)
+*/
+func (p TransverseMercator) Reverse(easting, northing float64) (lat, lon, convergence, scale float64) {
+	e := p.ellipsoid
+	f := e.DistanceFactor
+	phi, lam, gamma, k := p.tm.reverse(p.lon0, easting*f-p.fe, northing*f-p.fn+p.y0)
+	return e.angleOut(phi), e.angleOut(e.adjustLongitude(lam, pi)), e.angleOut(gamma), k
+}
@@ -0,0 +1,87 @@
+package ellipsoid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTransverseMercator(t *testing.T) {
+	// British National Grid, from EPSG Guidance Note 7-2.
+	airy, _ := New("AIRY")
+	bng, err := airy.NewTransverseMercator(49, -2, 0.9996012717, 400000, -100000)
+	if err != nil {
+		t.Fatalf("NewTransverseMercator: unexpected error %v", err)
+	}
+	easting, northing, _, _ := bng.Forward(50.5, 0.5)
+	deltaWithin(t, loc(), easting, 577274.99, 0.01)
+	deltaWithin(t, loc(), northing, 69740.50, 0.01)
+	lat, lon, _, _ := bng.Reverse(577274.99, 69740.50)
+	deltaWithin(t, loc(), lat, 50.5, 1e-7)
+	deltaWithin(t, loc(), lon, 0.5, 1e-7)
+
+	// The origin.
+	easting, northing, convergence, scale := bng.Forward(49, -2)
+	deltaWithin(t, loc(), easting, 400000, 1e-6)
+	deltaWithin(t, loc(), northing, -100000, 1e-6)
+	deltaWithin(t, loc(), convergence, 0, 1e-12)
+	deltaWithin(t, loc(), scale, 0.9996012717, 1e-12)
+	lat0, lon0 := bng.Origin()
+	deltaWithin(t, loc(), lat0, 49, 1e-12)
+	deltaWithin(t, loc(), lon0, -2, 1e-12)
+
+	// UTM is a transverse Mercator grid.
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+	utm34s, err := e.NewTransverseMercator(0, 21, 0.9996, 500000, 10000000)
+	if err != nil {
+		t.Fatalf("NewTransverseMercator: unexpected error %v", err)
+	}
+	u, _ := e.ToUTM(-33.9, 18.4)
+	easting, northing, convergence, scale = utm34s.Forward(-33.9, 18.4)
+	deltaWithin(t, loc(), easting, u.Easting, 1e-9)
+	deltaWithin(t, loc(), northing, u.Northing, 1e-9)
+	deltaWithin(t, loc(), convergence, u.Convergence, 1e-12)
+	deltaWithin(t, loc(), scale, u.Scale, 1e-12)
+
+	// A Gauss-Krüger zone, far from the central meridian.
+	gk, err := e.NewTransverseMercator(0, 9, 1, 3500000, 0)
+	if err != nil {
+		t.Fatalf("NewTransverseMercator: unexpected error %v", err)
+	}
+	for _, p := range [][2]float64{{50, 9}, {50, 30}, {-20, -15}, {70, 60}, {0, 40}} {
+		easting, northing, convergence, scale = gk.Forward(p[0], p[1])
+		lat, lon, convergence2, scale2 := gk.Reverse(easting, northing)
+		deltaWithin(t, loc(), lat, p[0], 1e-12)
+		deltaWithin(t, loc(), lon, p[1], 1e-11)
+		deltaWithin(t, loc(), convergence2, convergence, 1e-10)
+		deltaWithin(t, loc(), scale2, scale, 1e-12)
+	}
+	_, _, _, scale = gk.Forward(35, 9)
+	deltaWithin(t, loc(), scale, 1, 1e-15)
+
+	// Units.
+	k := Init("AIRY", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	bngk, err := k.NewTransverseMercator(49*degree, -2*degree, 0.9996012717, 400, -100)
+	if err != nil {
+		t.Fatalf("NewTransverseMercator: unexpected error %v", err)
+	}
+	easting, northing, _, _ = bngk.Forward(50.5*degree, 0.5*degree)
+	deltaWithin(t, loc(), easting, 577.27499, 1e-5)
+	deltaWithin(t, loc(), northing, 69.74050, 1e-5)
+	lat, lon, _, _ = bngk.Reverse(easting, northing)
+	deltaWithin(t, loc(), lat, 50.5*degree, 1e-13)
+	deltaWithin(t, loc(), lon, 0.5*degree, 1e-13)
+}
+
+func TestTransverseMercatorErrors(t *testing.T) {
+	e, _ := New("WGS84")
+	var outOfRange *OutOfRangeError
+	for _, k0 := range []float64{0, -1, math.Inf(1), math.NaN()} {
+		if _, err := e.NewTransverseMercator(0, 9, k0, 0, 0); !errors.As(err, &outOfRange) || outOfRange.Param != "scale factor" {
+			t.Errorf("NewTransverseMercator: expected OutOfRangeError for k0 %v, got %v", k0, err)
+		}
+	}
+	if _, err := e.NewTransverseMercator(91, 9, 1, 0, 0); !errors.As(err, &outOfRange) || outOfRange.Param != "latitude of origin" {
+		t.Errorf("NewTransverseMercator: expected OutOfRangeError for the latitude, got %v", err)
+	}
+}
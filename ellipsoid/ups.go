@@ -0,0 +1,165 @@
+package ellipsoid
+
+import "math"
+
+// The parameters of UPS in meters.
+const (
+	upsK0          = 0.994
+	upsFalseOrigin = 2000e3 // the false easting and northing
+)
+
+// UPSZone is the zone of a UTM point in the Universal Polar
+// Stereographic grid.
+const UPSZone = 0
+
+// polarStereographic holds the constants of the polar stereographic
+// projection of an ellipse with the scale k0 at the pole, following
+// GeographicLib.
+type polarStereographic struct {
+	a, k0   float64
+	es      float64 // the signed eccentricity
+	e2, e2m float64 // e^2 and 1 - e^2
+	c       float64 // 2 k0 a / c is the radius of the unit circle
+}
+
+func newPolarStereographic(e ellipse, k0 float64) *polarStereographic {
+	e2 := e.eccentricitySquared()
+	ps := &polarStereographic{a: e.Equatorial, k0: k0, es: e.eccentricity(), e2: e2, e2m: 1 - e2}
+	ps.c = math.Sqrt(ps.e2m) * math.Exp(eatanhe(1, ps.es))
+	return ps
+}
+
+// forward returns x, y in meters, the convergence gamma and the scale k
+// of the point phi, lam in radians on the projection centred on the
+// north or the south pole.
+func (ps *polarStereographic) forward(north bool, phi, lam float64) (x, y, gamma, k float64) {
+	if !north {
+		phi = -phi
+	}
+	tau := math.Tan(phi)
+	secphi := math.Hypot(1, tau)
+	taup := taupf(tau, ps.es)
+	rho := math.Hypot(1, taup) + math.Abs(taup)
+	if taup >= 0 {
+		if phi != pi/2 {
+			rho = 1 / rho
+		} else {
+			rho = 0
+		}
+	}
+	rho *= 2 * ps.k0 * ps.a / ps.c
+	if phi != pi/2 {
+		k = rho / ps.a * secphi * math.Sqrt(ps.e2m+ps.e2/sq(secphi))
+	} else {
+		k = ps.k0
+	}
+	x = rho * math.Sin(lam)
+	y = rho * math.Cos(lam)
+	if north {
+		y = -y
+		gamma = lam
+	} else {
+		gamma = -lam
+	}
+	return x, y, math.Remainder(gamma, twopi), k
+}
+
+// reverse is the inverse of forward.
+func (ps *polarStereographic) reverse(north bool, x, y float64) (phi, lam, gamma, k float64) {
+	rho := math.Hypot(x, y)
+	t := dblEpsilon * dblEpsilon
+	if rho != 0 {
+		t = rho / (2 * ps.k0 * ps.a / ps.c)
+	}
+	taup := (1/t - t) / 2
+	tau := tauf(taup, ps.es)
+	secphi := math.Hypot(1, tau)
+	if rho != 0 {
+		k = rho / ps.a * secphi * math.Sqrt(ps.e2m+ps.e2/sq(secphi))
+	} else {
+		k = ps.k0
+	}
+	phi = math.Atan(tau)
+	if north {
+		lam = math.Atan2(x, -y)
+		gamma = lam
+	} else {
+		phi = -phi
+		lam = math.Atan2(x, y)
+		gamma = -lam
+	}
+	return phi, lam, gamma, k
+}
+
+/*
+ToUPS returns the Universal Polar Stereographic coordinates of lat, lon
+in the hemisphere of lat. The Zone of the result is UPSZone. UPS is
+meant for the latitudes poleward of UTM but works up to the equator.
+
+	u, err := geo.ToUPS(lat, lon)
+	fmt.Println(u.North, u.Easting, u.Northing)
+*/
+func (ellipsoid Ellipsoid) ToUPS(lat, lon float64) (UTM, error) {
+	phi, lam := ellipsoid.angleIn(lat), ellipsoid.angleIn(lon)
+	if !(math.Abs(phi) <= pi/2) {
+		return UTM{}, &OutOfRangeError{Param: "latitude", Value: lat}
+	}
+	north := !math.Signbit(phi)
+	ps := newPolarStereographic(ellipsoid.Ellipse, upsK0)
+	x, y, gamma, k := ps.forward(north, phi, lam)
+	f := ellipsoid.DistanceFactor
+	return UTM{
+		Zone:        UPSZone,
+		North:       north,
+		Easting:     (x + upsFalseOrigin) / f,
+		Northing:    (y + upsFalseOrigin) / f,
+		Convergence: ellipsoid.angleOut(gamma),
+		Scale:       k,
+	}, nil
+}
+
+/*
+FromUPS is the inverse of ToUPS. It returns the latitude and longitude
+of the point easting, northing on the north or south polar grid.
+
+	lat, lon, err := geo.FromUPS(u.North, u.Easting, u.Northing)
+*/
+func (ellipsoid Ellipsoid) FromUPS(north bool, easting, northing float64) (lat, lon float64, err error) {
+	f := ellipsoid.DistanceFactor
+	x := easting*f - upsFalseOrigin
+	y := northing*f - upsFalseOrigin
+	ps := newPolarStereographic(ellipsoid.Ellipse, upsK0)
+	phi, lam, _, _ := ps.reverse(north, x, y)
+	return ellipsoid.angleOut(phi), ellipsoid.angleOut(ellipsoid.adjustLongitude(lam, pi)), nil
+}
+
+/*
+ToUTMUPS returns the UTM coordinates of lat, lon in the standard zone
+between 80S and 84N and the UPS coordinates, with the Zone UPSZone,
+beyond. Polar locations need no special case.
+
+	u, err := geo.ToUTMUPS(lat, lon)
+*/
+func (ellipsoid Ellipsoid) ToUTMUPS(lat, lon float64) (UTM, error) {
+	latd := lat
+	if ellipsoid.Units == Radians {
+		latd = rad2deg(lat)
+	}
+	if latd >= utmMinLatitude && latd < utmMaxLatitude {
+		return ellipsoid.ToUTM(lat, lon)
+	}
+	return ellipsoid.ToUPS(lat, lon)
+}
+
+/*
+FromUTMUPS is the inverse of ToUTMUPS. The zone UPSZone selects UPS.
+
+	lat, lon, err := geo.FromUTMUPS(u.Zone, u.North, u.Easting, u.Northing)
+*/
+func (ellipsoid Ellipsoid) FromUTMUPS(zone int, north bool, easting, northing float64) (lat, lon float64, err error) {
+	if zone == UPSZone {
+		return ellipsoid.FromUPS(north, easting, northing)
+	}
+	lat, lon, _, _, err = ellipsoid.FromUTM(zone, north, easting, northing)
+	return lat, lon, err
+}
@@ -0,0 +1,83 @@
+package ellipsoid
+
+import (
+	"testing"
+)
+
+func TestToUPS(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	for _, c := range []struct {
+		lat, lon           float64
+		north              bool
+		easting, northing  float64
+		convergence, scale float64
+	}{
+		{90, 0, true, 2000000, 2000000, 0, 0.994},
+		{-90, 0, false, 2000000, 2000000, 0, 0.994},
+		{84, 0, true, 2000000, 1333272.296316, 0, 0.996729995088},
+		{87, 90, true, 2333144.485446, 2000000, 90, 0.994681581978},
+		{-85.5, 0, false, 2000000, 2499853.763439, 0, 0.995534418355},
+		{-85.5, -90, false, 1500146.236561, 2000000, 90, 0.995534418355},
+	} {
+		u, err := e.ToUPS(c.lat, c.lon)
+		if err != nil || u.Zone != UPSZone || u.North != c.north {
+			t.Errorf("ToUPS(%v, %v) = %+v, %v", c.lat, c.lon, u, err)
+		}
+		deltaWithin(t, loc(), u.Easting, c.easting, 1e-6)
+		deltaWithin(t, loc(), u.Northing, c.northing, 1e-6)
+		deltaWithin(t, loc(), u.Convergence, c.convergence, 1e-12)
+		deltaWithin(t, loc(), u.Scale, c.scale, 1e-12)
+
+		lat, lon, err := e.FromUPS(u.North, u.Easting, u.Northing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deltaWithin(t, loc(), lat, c.lat, 1e-12)
+		if c.lat != 90 && c.lat != -90 {
+			deltaWithin(t, loc(), lon, c.lon, 1e-11)
+		}
+	}
+
+	if _, err := e.ToUPS(90.5, 0); err == nil {
+		t.Errorf("ToUPS(90.5, 0) did not fail")
+	}
+
+	k := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	u, _ := k.ToUPS(87*degree, pi/2)
+	deltaWithin(t, loc(), u.Easting, 2333.144485446, 1e-9)
+	deltaWithin(t, loc(), u.Convergence, pi/2, 1e-12)
+	lat, lon, _ := k.FromUPS(true, u.Easting, u.Northing)
+	deltaWithin(t, loc(), lat, 87*degree, 1e-13)
+	deltaWithin(t, loc(), lon, pi/2, 1e-13)
+}
+
+func TestUTMUPS(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	for _, c := range []struct {
+		lat, lon float64
+		zone     int
+	}{
+		{83.9, 10, 33}, {84, 10, UPSZone}, {89.99, -170, UPSZone},
+		{-80, 10, 32}, {-80.01, 10, UPSZone}, {0, 0, 31},
+	} {
+		u, err := e.ToUTMUPS(c.lat, c.lon)
+		if err != nil || u.Zone != c.zone {
+			t.Errorf("ToUTMUPS(%v, %v) = %+v, %v", c.lat, c.lon, u, err)
+		}
+		lat, lon, err := e.FromUTMUPS(u.Zone, u.North, u.Easting, u.Northing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deltaWithin(t, loc(), lat, c.lat, 1e-11)
+		deltaWithin(t, loc(), lon, c.lon, 1e-11)
+	}
+
+	if _, err := e.ToUTMUPS(-91, 0); err == nil {
+		t.Errorf("ToUTMUPS(-91, 0) did not fail")
+	}
+	if _, _, err := e.FromUTMUPS(61, true, 500000, 0); err == nil {
+		t.Errorf("FromUTMUPS in zone 61 did not fail")
+	}
+}
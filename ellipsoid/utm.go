@@ -0,0 +1,140 @@
+package ellipsoid
+
+import "math"
+
+// The parameters of UTM in meters.
+const (
+	utmK0             = 0.9996
+	utmFalseEasting   = 500e3
+	utmFalseNorthing  = 10000e3 // in the southern hemisphere
+	utmMinLatitude    = -80
+	utmMaxLatitude    = 84
+	utmMinZone        = 1
+	utmMaxZone        = 60
+	utmZoneWidth      = 6
+	utmMeridianOffset = -183 // of zone 0
+)
+
+// UTM is a point in the Universal Transverse Mercator grid, or in the
+// Universal Polar Stereographic grid for the zone UPSZone. Easting and
+// northing are in the distance units, the convergence in the units of
+// the Ellipsoid.
+type UTM struct {
+	Zone        int  // 1 to 60, or UPSZone
+	North       bool // the hemisphere
+	Easting     float64
+	Northing    float64
+	Convergence float64 // the angle from true north to grid north
+	Scale       float64 // the point scale factor
+}
+
+// latitudeBand returns the index of the MGRS latitude band of lat in
+// degrees, from -10 for C at 80S to 9 for X at 72N. The bands are 8
+// degrees tall.
+func latitudeBand(lat float64) int {
+	band := (int(math.Floor(lat))+80)/8 - 10
+	if band < -10 {
+		band = -10
+	} else if band > 9 {
+		band = 9 // X is 12 degrees tall
+	}
+	return band
+}
+
+// standardUTMZone returns the UTM zone of lat, lon in degrees, with the
+// exceptions for Norway and Svalbard.
+func standardUTMZone(lat, lon float64) int {
+	lon = math.Remainder(lon, 360)
+	ilon := int(math.Floor(lon))
+	if ilon == 180 {
+		ilon = -180
+	}
+	zone := (ilon + 186) / 6
+	switch band := latitudeBand(lat); {
+	case band == 7 && zone == 31 && ilon >= 3: // Norway
+		zone = 32
+	case band == 9 && ilon >= 0 && ilon < 42: // Svalbard
+		zone = 2*((ilon+183)/12) + 1
+	}
+	return zone
+}
+
+// utmCentralMeridian returns the central meridian of zone in radians.
+func utmCentralMeridian(zone int) float64 {
+	return float64(utmZoneWidth*zone+utmMeridianOffset) * degree
+}
+
+/*
+ToUTM returns the UTM coordinates of lat, lon in the standard zone,
+including the exceptions for Norway and Svalbard. UTM covers the
+latitudes from 80S to 84N; outside it returns an OutOfRangeError. Use
+ToUTMUPS for the whole earth.
+
+	u, err := geo.ToUTM(lat, lon)
+	fmt.Println(u.Zone, u.North, u.Easting, u.Northing)
+*/
+func (ellipsoid Ellipsoid) ToUTM(lat, lon float64) (UTM, error) {
+	latd, lond := lat, lon
+	if ellipsoid.Units == Radians {
+		latd, lond = rad2deg(lat), rad2deg(lon)
+	}
+	if !(latd >= utmMinLatitude && latd < utmMaxLatitude) {
+		return UTM{}, &OutOfRangeError{Param: "UTM latitude", Value: lat}
+	}
+	return ellipsoid.ToUTMZone(lat, lon, standardUTMZone(latd, lond))
+}
+
+/*
+ToUTMZone is ToUTM in the given zone instead of the standard one, e.g.
+to keep a survey that straddles a zone boundary in one grid. It works
+for any latitude, but the distortion grows quickly away from the
+zone.
+
+	u, err := geo.ToUTMZone(lat, lon, 32)
+*/
+func (ellipsoid Ellipsoid) ToUTMZone(lat, lon float64, zone int) (UTM, error) {
+	if zone < utmMinZone || zone > utmMaxZone {
+		return UTM{}, &OutOfRangeError{Param: "UTM zone", Value: float64(zone)}
+	}
+	phi, lam := ellipsoid.angleIn(lat), ellipsoid.angleIn(lon)
+	if !(math.Abs(phi) <= pi/2) {
+		return UTM{}, &OutOfRangeError{Param: "latitude", Value: lat}
+	}
+	tm := cachedTransverseMercator(ellipsoid.Ellipse, utmK0)
+	x, y, gamma, k := tm.forward(utmCentralMeridian(zone), phi, lam)
+	north := !math.Signbit(phi)
+	if !north {
+		y += utmFalseNorthing
+	}
+	f := ellipsoid.DistanceFactor
+	return UTM{
+		Zone:        zone,
+		North:       north,
+		Easting:     (x + utmFalseEasting) / f,
+		Northing:    y / f,
+		Convergence: ellipsoid.angleOut(gamma),
+		Scale:       k,
+	}, nil
+}
+
+/*
+FromUTM is the inverse of ToUTM. It returns the latitude and longitude
+of the point easting, northing in the zone and hemisphere, with the
+convergence and the point scale there.
+
+	lat, lon, convergence, scale, err := geo.FromUTM(u.Zone, u.North, u.Easting, u.Northing)
+*/
+func (ellipsoid Ellipsoid) FromUTM(zone int, north bool, easting, northing float64) (lat, lon, convergence, scale float64, err error) {
+	if zone < utmMinZone || zone > utmMaxZone {
+		return 0, 0, 0, 0, &OutOfRangeError{Param: "UTM zone", Value: float64(zone)}
+	}
+	f := ellipsoid.DistanceFactor
+	x := easting*f - utmFalseEasting
+	y := northing * f
+	if !north {
+		y -= utmFalseNorthing
+	}
+	tm := cachedTransverseMercator(ellipsoid.Ellipse, utmK0)
+	phi, lam, gamma, k := tm.reverse(utmCentralMeridian(zone), x, y)
+	return ellipsoid.angleOut(phi), ellipsoid.angleOut(ellipsoid.adjustLongitude(lam, pi)), ellipsoid.angleOut(gamma), k, nil
+}
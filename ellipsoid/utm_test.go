@@ -0,0 +1,121 @@
+package ellipsoid
+
+import (
+	"testing"
+)
+
+func TestToUTM(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	// From the documentation of GeographicLib's GeoConvert.
+	u, err := e.ToUTM(33.3, 44.4)
+	if err != nil || u.Zone != 38 || !u.North {
+		t.Fatalf("ToUTM(33.3, 44.4) = %+v, %v", u, err)
+	}
+	deltaWithin(t, loc(), u.Easting, 444140.545, 1e-3)
+	deltaWithin(t, loc(), u.Northing, 3684706.356, 1e-3)
+	deltaWithin(t, loc(), u.Convergence, -0.329422222, 1e-9)
+	deltaWithin(t, loc(), u.Scale, 0.999638469, 1e-9)
+
+	u, _ = e.ToUTM(0, 0)
+	deltaWithin(t, loc(), u.Easting, 166021.443, 1e-3)
+	deltaWithin(t, loc(), u.Northing, 0, 1e-9)
+
+	// On the central meridian.
+	u, _ = e.ToUTM(-33, 21)
+	if u.Zone != 34 || u.North {
+		t.Errorf("ToUTM(-33, 21) = %+v", u)
+	}
+	deltaWithin(t, loc(), u.Easting, 500000, 1e-9)
+	deltaWithin(t, loc(), u.Northing, 10000000+0.9996*e.MeridianArc(-33), 1e-6)
+	deltaWithin(t, loc(), u.Scale, 0.9996, 1e-15)
+
+	// The standard zones and the exceptions for Norway and Svalbard.
+	for _, c := range []struct {
+		lat, lon float64
+		zone     int
+	}{
+		{0, -180, 1}, {0, 179.9, 60}, {0, 180, 1}, {10, 2.9, 31},
+		{55.9, 5, 31}, {56, 5, 32}, {63.9, 2.9, 31}, {64, 5, 31},
+		{71.9, 8, 32}, {72, 8, 31}, {72, 9, 33}, {72, 20.9, 33},
+		{72, 21, 35}, {83.9, 33, 37}, {72, 42, 38},
+	} {
+		if u, _ := e.ToUTM(c.lat, c.lon); u.Zone != c.zone {
+			t.Errorf("ToUTM(%v, %v) in zone %d, want %d", c.lat, c.lon, u.Zone, c.zone)
+		}
+	}
+
+	for _, lat := range []float64{84, -80.1, 90} {
+		if _, err := e.ToUTM(lat, 0); err == nil {
+			t.Errorf("ToUTM(%v, 0) did not fail", lat)
+		}
+	}
+
+	k := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	u, _ = k.ToUTM(33.3*degree, 44.4*degree)
+	deltaWithin(t, loc(), u.Easting, 444.140545, 1e-6)
+	deltaWithin(t, loc(), u.Northing, 3684.706356, 1e-6)
+	deltaWithin(t, loc(), u.Convergence, -0.329422222*degree, 1e-9)
+}
+
+func TestToUTMZone(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	u, err := e.ToUTMZone(33.3, 44.4, 37)
+	if err != nil || u.Zone != 37 || !u.North {
+		t.Fatalf("ToUTMZone = %+v, %v", u, err)
+	}
+	if u.Easting < 1000000 {
+		t.Errorf("ToUTMZone easting %v is not east of zone 37", u.Easting)
+	}
+	lat, lon, _, _, _ := e.FromUTM(37, true, u.Easting, u.Northing)
+	deltaWithin(t, loc(), lat, 33.3, 1e-11)
+	deltaWithin(t, loc(), lon, 44.4, 1e-11)
+
+	// Zone 31 at Bergen instead of the Norway exception.
+	u, _ = e.ToUTMZone(60.39, 5.32, 31)
+	lat, lon, _, _, _ = e.FromUTM(u.Zone, u.North, u.Easting, u.Northing)
+	deltaWithin(t, loc(), lat, 60.39, 1e-11)
+	deltaWithin(t, loc(), lon, 5.32, 1e-11)
+
+	for _, zone := range []int{0, 61} {
+		if _, err := e.ToUTMZone(0, 0, zone); err == nil {
+			t.Errorf("ToUTMZone in zone %d did not fail", zone)
+		}
+		if _, _, _, _, err := e.FromUTM(zone, true, 500000, 0); err == nil {
+			t.Errorf("FromUTM in zone %d did not fail", zone)
+		}
+	}
+}
+
+func TestFromUTM(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeNotSymmetric, BearingNotSymmetric)
+
+	lat, lon, _, _, err := e.FromUTM(38, true, 444140.545, 3684706.356)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltaWithin(t, loc(), lat, 33.3, 1e-8)
+	deltaWithin(t, loc(), lon, 44.4, 1e-8)
+
+	for lat := -80.0; lat < 84; lat += 9.5 {
+		for lon := -180.0; lon < 180; lon += 23.3 {
+			u, _ := e.ToUTM(lat, lon)
+			lat2, lon2, convergence, scale, _ := e.FromUTM(u.Zone, u.North, u.Easting, u.Northing)
+			deltaWithin(t, loc(), lat2, lat, 1e-11)
+			deltaWithin(t, loc(), convergence, u.Convergence, 1e-10)
+			deltaWithin(t, loc(), scale, u.Scale, 1e-12)
+			if lon < 0 {
+				lon += 360
+			}
+			deltaWithin(t, loc(), lon2, lon, 1e-11)
+		}
+	}
+
+	m := Init("WGS84", Radians, Foot, LongitudeIsSymmetric, BearingNotSymmetric)
+	lat, lon, convergence, _, _ := m.FromUTM(38, true, 444140.545/0.3048, 3684706.356/0.3048)
+	deltaWithin(t, loc(), lat, 33.3*degree, 1e-10)
+	deltaWithin(t, loc(), lon, 44.4*degree, 1e-10)
+	u, _ := m.ToUTM(33.3*degree, 44.4*degree)
+	deltaWithin(t, loc(), convergence, u.Convergence, 1e-8)
+}
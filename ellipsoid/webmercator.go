@@ -0,0 +1,166 @@
+package ellipsoid
+
+// Web Mercator (EPSG:3857) and the tiles of slippy maps. Web Mercator
+// projects the latitude and longitude on the sphere with the radius
+// 6378137 m, whatever the ellipsoid; it is not conformal on the
+// ellipsoid. The tiles are numbered from the north-west corner of the
+// map, which is cut off at about 85.0511 degrees.
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	webMercatorRadius = 6378137.0 // meters
+	// TileSize is the width and height of a tile in pixels.
+	TileSize = 256
+	// MaxZoom is the highest zoom level with int tile indices everywhere.
+	MaxZoom = 30
+)
+
+// webMercatorMaxLatitude is the latitude of the edge of the square map,
+// about 85.0511 degrees, in radians.
+var webMercatorMaxLatitude = math.Atan(math.Sinh(pi))
+
+// Tile is a tile of a slippy map. X counts from the antimeridian to the
+// east, Y from the north edge of the map to the south.
+type Tile struct {
+	X, Y, Zoom int
+}
+
+/*
+ToWebMercator returns the Web Mercator coordinates x, y of lat, lon in
+the distance units. y is infinite at the poles.
+
+	x, y := geo.ToWebMercator(lat, lon)
+*/
+func (ellipsoid Ellipsoid) ToWebMercator(lat, lon float64) (x, y float64) {
+	phi := ellipsoid.angleIn(lat)
+	lam := math.Remainder(ellipsoid.angleIn(lon), twopi)
+	f := ellipsoid.DistanceFactor
+	return webMercatorRadius * lam / f, webMercatorRadius * math.Asinh(math.Tan(phi)) / f
+}
+
+/*
+FromWebMercator is the inverse of ToWebMercator.
+
+	lat, lon := geo.FromWebMercator(x, y)
+*/
+func (ellipsoid Ellipsoid) FromWebMercator(x, y float64) (lat, lon float64) {
+	f := ellipsoid.DistanceFactor
+	phi := math.Atan(math.Sinh(y * f / webMercatorRadius))
+	lam := ellipsoid.adjustLongitude(math.Remainder(x*f/webMercatorRadius, twopi), pi)
+	return ellipsoid.angleOut(phi), ellipsoid.angleOut(lam)
+}
+
+/*
+ToTile returns the tile at the zoom level that contains lat, lon.
+Latitudes beyond the edge of the map are clipped to it.
+
+	t, err := geo.ToTile(lat, lon, 12)
+	url := fmt.Sprintf("https://tile.openstreetmap.org/%d/%d/%d.png", t.Zoom, t.X, t.Y)
+*/
+func (ellipsoid Ellipsoid) ToTile(lat, lon float64, zoom int) (Tile, error) {
+	if zoom < 0 || zoom > MaxZoom {
+		return Tile{}, &OutOfRangeError{Param: "zoom", Value: float64(zoom)}
+	}
+	phi := math.Max(-webMercatorMaxLatitude, math.Min(webMercatorMaxLatitude, ellipsoid.angleIn(lat)))
+	lam := math.Remainder(ellipsoid.angleIn(lon), twopi)
+	if lam == pi {
+		lam = -pi // the antimeridian is the west edge of the map
+	}
+	n := float64(int(1) << uint(zoom))
+	clip := func(v float64) int {
+		return int(math.Max(0, math.Min(n-1, math.Floor(v*n))))
+	}
+	return Tile{
+		X:    clip((lam/pi + 1) / 2),
+		Y:    clip((1 - math.Asinh(math.Tan(phi))/pi) / 2),
+		Zoom: zoom,
+	}, nil
+}
+
+// tileLatitude returns the latitude in radians of the north edge of the
+// row y of tiles at a zoom level with n rows.
+func tileLatitude(y, n float64) float64 {
+	return math.Atan(math.Sinh(pi * (1 - 2*y/n)))
+}
+
+/*
+TileBounds returns the latitudes of the south and north edges and the
+longitudes of the west and east edges of the tile. The error is an
+*OutOfRangeError if the zoom level or the tile is not on the map.
+
+	south, west, north, east, err := geo.TileBounds(t)
+*/
+func (ellipsoid Ellipsoid) TileBounds(t Tile) (south, west, north, east float64, err error) {
+	if t.Zoom < 0 || t.Zoom > MaxZoom {
+		return 0, 0, 0, 0, &OutOfRangeError{Param: "zoom", Value: float64(t.Zoom)}
+	}
+	size := int(1) << uint(t.Zoom)
+	if t.X < 0 || t.X >= size {
+		return 0, 0, 0, 0, &OutOfRangeError{Param: "tile x", Value: float64(t.X)}
+	}
+	if t.Y < 0 || t.Y >= size {
+		return 0, 0, 0, 0, &OutOfRangeError{Param: "tile y", Value: float64(t.Y)}
+	}
+	n := float64(size)
+	w := float64(t.X)/n*twopi - pi
+	e := float64(t.X+1)/n*twopi - pi
+	return ellipsoid.angleOut(tileLatitude(float64(t.Y+1), n)), ellipsoid.angleOut(w),
+		ellipsoid.angleOut(tileLatitude(float64(t.Y), n)), ellipsoid.angleOut(e), nil
+}
+
+/*
+GroundResolution returns the distance on the ellipsoid covered by one
+pixel at the latitude lat and the zoom level in the distance units. It
+is measured along the parallel, on the real ellipsoid rather than on the
+sphere of Web Mercator.
+
+	metersPerPixel := geo.GroundResolution(lat, 12)
+*/
+func (ellipsoid Ellipsoid) GroundResolution(lat float64, zoom int) float64 {
+	e := ellipsoid.Ellipse
+	parallel := e.Equatorial * e.parallelScale(ellipsoid.angleIn(lat))
+	return twopi * parallel / (TileSize * math.Ldexp(1, zoom)) / ellipsoid.DistanceFactor
+}
+
+// Quadkey returns the quadkey of the tile, the path of quadrants from
+// zoom level 1 to the zoom level of the tile, as used by Bing Maps.
+func (t Tile) Quadkey() string {
+	var b strings.Builder
+	for i := t.Zoom; i > 0; i-- {
+		digit := byte('0')
+		mask := 1 << uint(i-1)
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		b.WriteByte(digit)
+	}
+	return b.String()
+}
+
+/*
+TileFromQuadkey is the inverse of Tile.Quadkey.
+
+	t, err := ellipsoid.TileFromQuadkey("120210233")
+*/
+func TileFromQuadkey(quadkey string) (Tile, error) {
+	t := Tile{Zoom: len(quadkey)}
+	if t.Zoom > MaxZoom {
+		return Tile{}, &OutOfRangeError{Param: "zoom", Value: float64(t.Zoom)}
+	}
+	for i := 0; i < len(quadkey); i++ {
+		d := quadkey[i]
+		if d < '0' || d > '3' {
+			return Tile{}, &InvalidQuadkeyError{Quadkey: quadkey}
+		}
+		t.X = t.X<<1 | int(d-'0')&1
+		t.Y = t.Y<<1 | int(d-'0')>>1
+	}
+	return t, nil
+}
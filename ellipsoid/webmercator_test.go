@@ -0,0 +1,131 @@
+package ellipsoid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestWebMercator(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	x, y := e.ToWebMercator(0, 180)
+	deltaWithin(t, loc(), math.Abs(x), 20037508.342789244, 1e-6)
+	deltaWithin(t, loc(), y, 0, 1e-9)
+	x, y = e.ToWebMercator(rad2deg(webMercatorMaxLatitude), -90)
+	deltaWithin(t, loc(), x, -20037508.342789244/2, 1e-6)
+	deltaWithin(t, loc(), y, 20037508.342789244, 1e-6)
+	deltaWithin(t, loc(), rad2deg(webMercatorMaxLatitude), 85.0511287798, 1e-10)
+
+	for _, p := range [][2]float64{{52.52, 13.405}, {-33.9, 151.2}, {0, -179.9}, {80, 0}} {
+		x, y = e.ToWebMercator(p[0], p[1])
+		lat, lon := e.FromWebMercator(x, y)
+		deltaWithin(t, loc(), lat, p[0], 1e-12)
+		deltaWithin(t, loc(), lon, p[1], 1e-12)
+	}
+
+	// The projection is on the sphere, also for other ellipsoids.
+	k, _ := New("AIRY", WithUnits(Radians), WithDistanceUnits(Kilometer))
+	x, y = k.ToWebMercator(52.52*degree, 13.405*degree)
+	x2, y2 := e.ToWebMercator(52.52, 13.405)
+	deltaWithin(t, loc(), x, x2/1000, 1e-9)
+	deltaWithin(t, loc(), y, y2/1000, 1e-9)
+}
+
+func TestTiles(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	for _, c := range []struct {
+		lat, lon float64
+		zoom     int
+		tile     Tile
+	}{
+		{0, 0, 0, Tile{0, 0, 0}},
+		{0, 0, 1, Tile{1, 1, 1}},
+		{52.52, 13.405, 10, Tile{550, 335, 10}},
+		{-33.9, 151.2, 3, Tile{7, 4, 3}},
+		{90, -180, 5, Tile{0, 0, 5}},
+		{-90, 180, 5, Tile{0, 31, 5}},
+		{-90, 179.9999, 5, Tile{31, 31, 5}},
+	} {
+		if got, err := e.ToTile(c.lat, c.lon, c.zoom); err != nil || got != c.tile {
+			t.Errorf("ToTile(%v, %v, %d) = %v, %v, want %v", c.lat, c.lon, c.zoom, got, err, c.tile)
+		}
+	}
+	for _, zoom := range []int{-1, MaxZoom + 1} {
+		if _, err := e.ToTile(0, 0, zoom); err == nil {
+			t.Errorf("ToTile at zoom %d did not fail", zoom)
+		}
+	}
+
+	south, west, north, east, err := e.TileBounds(Tile{550, 335, 10})
+	if err != nil {
+		t.Fatalf("TileBounds: unexpected error %v", err)
+	}
+	if !(south < 52.52 && 52.52 < north && west < 13.405 && 13.405 < east) {
+		t.Errorf("TileBounds = %v, %v, %v, %v", south, west, north, east)
+	}
+	deltaWithin(t, loc(), east-west, 360.0/1024, 1e-12)
+	south, west, north, east, _ = e.TileBounds(Tile{0, 0, 0})
+	deltaWithin(t, loc(), south, -85.0511287798, 1e-10)
+	deltaWithin(t, loc(), north, 85.0511287798, 1e-10)
+	deltaWithin(t, loc(), west, -180, 1e-12)
+	deltaWithin(t, loc(), east, 180, 1e-12)
+
+	// The corners of a tile are in the tile.
+	r := Init("WGS84", Radians, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+	south, west, north, east, _ = r.TileBounds(Tile{1234, 567, 11})
+	if got, _ := r.ToTile(north, west, 11); got != (Tile{1234, 567, 11}) {
+		t.Errorf("ToTile of the north-west corner = %v", got)
+	}
+	if got, _ := r.ToTile(south, east, 11); got != (Tile{1235, 568, 11}) {
+		t.Errorf("ToTile of the south-east corner = %v", got)
+	}
+
+	var outOfRange *OutOfRangeError
+	for _, tile := range []Tile{{0, 0, -1}, {0, 0, MaxZoom + 1}, {0, 0, 63}, {2, 0, 1}, {0, -1, 1}} {
+		if _, _, _, _, err := e.TileBounds(tile); !errors.As(err, &outOfRange) {
+			t.Errorf("TileBounds of %v: expected OutOfRangeError, got %v", tile, err)
+		}
+	}
+}
+
+func TestQuadkey(t *testing.T) {
+	// From the Bing Maps Tile System documentation.
+	if got := (Tile{3, 5, 3}).Quadkey(); got != "213" {
+		t.Errorf("Quadkey = %q, want 213", got)
+	}
+	if got := (Tile{0, 0, 0}).Quadkey(); got != "" {
+		t.Errorf("Quadkey = %q", got)
+	}
+	for _, tile := range []Tile{{3, 5, 3}, {0, 0, 0}, {550, 335, 10}, {1<<30 - 1, 12345, 30}} {
+		got, err := TileFromQuadkey(tile.Quadkey())
+		if err != nil || got != tile {
+			t.Errorf("TileFromQuadkey(%q) = %v, %v, want %v", tile.Quadkey(), got, err, tile)
+		}
+	}
+	for _, q := range []string{"124", "21a", "0000000000000000000000000000000"} {
+		if _, err := TileFromQuadkey(q); err == nil {
+			t.Errorf("TileFromQuadkey(%q) did not fail", q)
+		}
+	}
+}
+
+func TestGroundResolution(t *testing.T) {
+	e := Init("WGS84", Degrees, Meter, LongitudeIsSymmetric, BearingNotSymmetric)
+
+	// From the Bing Maps Tile System documentation.
+	deltaWithin(t, loc(), e.GroundResolution(0, 0), 156543.03392, 1e-5)
+	deltaWithin(t, loc(), e.GroundResolution(0, 10), 156543.03392/1024, 1e-8)
+
+	// Along the parallel on the ellipsoid, which is longer than on the
+	// sphere.
+	_, lonScale := e.Scales(60)
+	deltaWithin(t, loc(), e.GroundResolution(60, 12), lonScale*360/(256*4096), 1e-9)
+	if sphere := 156543.03392 * 0.5 / 4096; !(e.GroundResolution(60, 12) > sphere) {
+		t.Errorf("GroundResolution(60, 12) = %v", e.GroundResolution(60, 12))
+	}
+
+	k := Init("WGS84", Radians, Kilometer, LongitudeIsSymmetric, BearingNotSymmetric)
+	deltaWithin(t, loc(), k.GroundResolution(60*degree, 12), e.GroundResolution(60, 12)/1000, 1e-12)
+}